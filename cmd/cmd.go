@@ -3,16 +3,18 @@ package cmd
 import (
 	"flag"
 	"fmt"
+	"gitoday/config"
 	"gitoday/global"
 	"gitoday/service"
+	"gitoday/storage"
 	"gitoday/ui/model"
 	"io"
 	"log"
 	"log/slog"
 	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/joho/godotenv"
 )
 
 var helpText = fmt.Sprintf(`  
@@ -24,6 +26,14 @@ The tool requires two command line arguments:
 
 Example of usage:
 ./gitoday -mode=debug -preview=true
+./gitoday -record=./fixtures
+./gitoday -replay=./fixtures
+./gitoday -spoken=zh
+./gitoday -config=./gitoday.yaml -theme=light
+./gitoday -provider=openai -endpoint=http://localhost:11434/v1 -model=qwen2.5
+./gitoday list -lang=go -since=weekly -format=json
+./gitoday export -lang=go -top=10 -format=markdown -o digest.md
+./gitoday history -lang=go -at=2024-06-04
   
 Please make sure that the queue name is valid according to Azure's naming rules.  
 `)
@@ -34,33 +44,92 @@ func die() {
 
 }
 func Execute() {
-	err := godotenv.Load()
-	if err != nil {
-		panic(err)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "list":
+			// headless mode does not need an api key
+			runList(os.Args[2:])
+			return
+		case "export":
+			runExport(os.Args[2:])
+			return
+		case "history":
+			runHistory(os.Args[2:])
+			return
+		}
 	}
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "%s", helpText)
 	}
 	var mode string
-	var apiKey string
-	var preview bool
 
 	flag.StringVar(&mode, "mode", "", "The environment to be used")
-	flag.BoolVar(&preview, "preview", false, "Use fake data, not fetch from github")
+	fixtures := fixtureFlags(flag.CommandLine)
+	flag.String("theme", "default", "The TUI theme, default or light")
+	trendingFlags(flag.CommandLine)
+	aiFlags(flag.CommandLine)
+	configPath := configFlag(flag.CommandLine)
 	flag.Parse()
-	apiKey = os.Getenv("API_KEY")
-	if len(apiKey) == 0 {
+	cfg := loadConfig(flag.CommandLine, *configPath)
+	// a local openai compatible server usually does not need a key
+	if len(cfg.AI.APIKey) == 0 && cfg.AI.Provider != service.ProviderOpenAI && !fixtures.replaying() {
+		die()
+	}
+	lang, since, spoken := trending(cfg)
+	if err := model.SetTheme(cfg.Theme); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		die()
+	}
+	if err := model.SetKeyBindings(cfg.Bindings()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
 		die()
 	}
 	initLogger(mode)
-	initGlobal(preview)
-	initService(apiKey)
-	slog.Info("Starting gitoday", slog.String("mode", mode), slog.String("apiKey", apiKey), slog.Bool("preview", preview))
+	initGlobal(fixtures, spoken)
+	global.SetLanguage(lang)
+	global.SetSince(since)
+	initService(cfg.AI)
+	model.SetConcurrency(cfg.AI.Concurrency)
+	initStorage()
+	defer storage.Close()
+	slog.Info("Starting gitoday", slog.String("mode", mode), slog.Bool("preview", fixtures.preview),
+		slog.String("record", fixtures.record), slog.String("replay", fixtures.replay), slog.String("lang", string(lang)),
+		slog.String("since", string(since)), slog.String("spoken", string(spoken)), slog.String("theme", cfg.Theme),
+		slog.String("provider", cfg.AI.Provider), slog.String("endpoint", cfg.AI.Endpoint), slog.String("model", cfg.AI.Model),
+		slog.Duration("cacheTTL", cfg.AI.CacheTTL))
 
 	initModel()
 }
-func initGlobal(preview bool) {
-	global.SetPreview(preview)
+
+// fixtureOptions choose where crawled pages and AI answers come from
+type fixtureOptions struct {
+	preview bool
+	record  string
+	replay  string
+}
+
+func (o *fixtureOptions) replaying() bool {
+	return o.preview || o.replay != ""
+}
+
+func fixtureFlags(fs *flag.FlagSet) *fixtureOptions {
+	o := &fixtureOptions{}
+	fs.BoolVar(&o.preview, "preview", false, "Replay the built-in fixtures, not fetch from github")
+	fs.StringVar(&o.record, "record", "", "Save the fetched pages and AI answers as fixtures under this directory")
+	fs.StringVar(&o.replay, "replay", "", "Replay the fixtures recorded under this directory, not fetch from github")
+	return o
+}
+
+func initGlobal(fixtures *fixtureOptions, spoken global.SpokenLanguage) {
+	// replayed data is kept out of the history and the analysis cache
+	global.SetPreview(fixtures.replaying())
+	global.SetSpokenLanguage(spoken)
+	switch {
+	case fixtures.replay != "":
+		service.Replay(os.DirFS(fixtures.replay))
+	case fixtures.record != "":
+		service.Record(fixtures.record)
+	}
 }
 func initLogger(mode string) {
 	if mode == "debug" {
@@ -81,8 +150,47 @@ func initLogger(mode string) {
 	}
 
 }
-func initService(apiKey string) {
-	service.Init(apiKey)
+
+// aiFlags are the flags shared by every command that asks AI, the api key is only read from the config or env
+func aiFlags(fs *flag.FlagSet) {
+	fs.String("provider", "dify", "The AI provider, dify or openai")
+	fs.String("locale", "en", "The language of AI answers and the TUI, en or zh")
+	fs.String("endpoint", "", "The AI provider endpoint, default is the provider's public api")
+	fs.String("model", "", "The model name used by the openai provider")
+	fs.String("prompt", "", "A text/template file replacing the built-in prompt, default is prompt.tmpl next to the config file if it exists")
+	fs.Duration("ai-timeout", 200*time.Second, "How long an AI analysis may take")
+	fs.Duration("cache-ttl", 7*24*time.Hour, "How long AI analyses are cached on disk, 0 disables the cache")
+	fs.Int("concurrency", 3, "How many AI requests run at the same time")
+}
+
+func initService(ai config.AI) {
+	p, err := service.NewProvider(ai.Provider, ai.Endpoint, ai.APIKey, ai.Model)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		die()
+	}
+	service.Init(p)
+	dir, err := service.DefaultCacheDir()
+	if err != nil {
+		slog.Error("no cache dir, analyses will not be cached", slog.String("error", err.Error()))
+		return
+	}
+	service.InitCache(dir, ai.CacheTTL)
+}
+func initStorage() {
+	path, err := storage.DefaultPath()
+	if err == nil {
+		err = storage.Init(path)
+	}
+	if err != nil {
+		slog.Error("history is disabled", slog.String("error", fmt.Sprintf("%+v", err)))
+	}
+	bookmarkPath, err := storage.DefaultBookmarkPath()
+	if err != nil {
+		slog.Error("bookmarks are disabled", slog.String("error", err.Error()))
+		return
+	}
+	storage.InitBookmarks(bookmarkPath)
 }
 func initModel() {
 	p := tea.NewProgram(model.NewModel(), tea.WithAltScreen())
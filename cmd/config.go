@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"gitoday/config"
+	"gitoday/global"
+	"gitoday/service"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// trendingFlags are the flags shared by every command that picks a trending page,
+// their values are read back from the config after the flags are applied.
+func trendingFlags(fs *flag.FlagSet) {
+	fs.String("lang", string(global.All), "The programming language of trending repositories, e.g. go, rust")
+	fs.String("since", string(global.Daily), "The trending window, daily, weekly or monthly")
+	fs.String("spoken", "", "The spoken language code of trending repositories, e.g. en, zh")
+	fs.Duration("crawl-timeout", 30*time.Second, "How long crawling a github page may take")
+	fs.Bool("enrich", false, "Add topics, license and activity of the repos from the GitHub API, set GITHUB_TOKEN to raise the rate limit")
+}
+
+func configFlag(fs *flag.FlagSet) *string {
+	return fs.String("config", "", "The config file, default is $XDG_CONFIG_HOME/gitoday/config.yaml")
+}
+
+// loadConfig reads the config file, then the env vars (a .env file is loaded too)
+// and at last the flags set on the command line override it.
+func loadConfig(fs *flag.FlagSet, path string) config.Config {
+	// a missing .env file is fine, the env may come from a cron job as well
+	_ = godotenv.Load()
+	// a config file given by -config has to be there, the one at the default path not
+	cfg, err := config.Default(), error(nil)
+	if path != "" {
+		cfg, err = config.Load(path)
+	} else if p, perr := config.DefaultPath(); perr == nil {
+		path = p
+		cfg, err = config.LoadOptional(path)
+	}
+	if err == nil {
+		err = cfg.ApplyEnv()
+	}
+	if err == nil {
+		err = cfg.ApplyFlags(fs)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config error: %v\n", err)
+		os.Exit(1)
+	}
+	locale, ok := global.ParseLocale(cfg.Locale)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown locale %q\n", cfg.Locale)
+		os.Exit(1)
+	}
+	global.SetLocale(locale)
+	promptPath := cfg.AI.Prompt
+	if promptPath == "" && path != "" {
+		if p := filepath.Join(filepath.Dir(path), "prompt.tmpl"); fileExists(p) {
+			promptPath = p
+		}
+	}
+	if promptPath != "" {
+		if err := service.SetPromptFile(promptPath); err != nil {
+			fmt.Fprintf(os.Stderr, "prompt error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	service.SetTimeouts(cfg.CrawlTimeout, cfg.AI.Timeout)
+	service.SetEnrich(cfg.Enrich)
+	if dir, err := service.DefaultGitHubCacheDir(); err == nil {
+		service.InitGitHubCache(dir)
+	}
+	return cfg
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// trending parses the trending page picked by the config, exits on an unknown value.
+func trending(cfg config.Config) (global.Language, global.Since, global.SpokenLanguage) {
+	since, ok := global.ParseSince(cfg.Since)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown trending window %q\n", cfg.Since)
+		os.Exit(1)
+	}
+	spoken, ok := global.ParseSpokenLanguage(cfg.Spoken)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown spoken language %q\n", cfg.Spoken)
+		os.Exit(1)
+	}
+	lang, ok := global.ParseLanguage(cfg.Language)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown language %q\n", cfg.Language)
+		os.Exit(1)
+	}
+	return lang, since, spoken
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"gitoday/service"
+	"gitoday/storage"
+	"os"
+	"time"
+)
+
+// runExport crawls github trending, asks AI about the top repositories and
+// renders them as a markdown or html digest.
+func runExport(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	var mode, format, templatePath, output string
+	var top int
+	fs.StringVar(&mode, "mode", "", "The environment to be used")
+	fixtures := fixtureFlags(fs)
+	trendingFlags(fs)
+	fs.IntVar(&top, "top", 10, "How many repositories are analysed")
+	fs.StringVar(&format, "format", service.DigestMarkdown, "The digest format, markdown or html")
+	fs.StringVar(&templatePath, "template", "", "A Go template file overriding the built-in digest layout")
+	fs.StringVar(&output, "o", "", "The output file, default is stdout")
+	aiFlags(fs)
+	configPath := configFlag(fs)
+	fs.Parse(args)
+
+	cfg := loadConfig(fs, *configPath)
+	lang, sinceWindow, spokenLanguage := trending(cfg)
+	if len(cfg.AI.APIKey) == 0 && cfg.AI.Provider != service.ProviderOpenAI && !fixtures.replaying() {
+		fmt.Fprintln(os.Stderr, "API_KEY is not set")
+		os.Exit(1)
+	}
+	initLogger(mode)
+	initGlobal(fixtures, spokenLanguage)
+	initService(cfg.AI)
+	initStorage()
+
+	repos, err := service.Crawl(lang, sinceWindow, spokenLanguage)
+	if err == nil {
+		storage.Record(lang, sinceWindow, spokenLanguage, repos)
+	}
+	// the history is done with, and os.Exit below would skip a deferred Close
+	storage.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "crawl error: %v\n", err)
+		os.Exit(1)
+	}
+	if top > 0 && len(repos) > top {
+		repos = repos[:top]
+	}
+	entries := service.Analyze(context.Background(), repos, cfg.AI.Concurrency, func(done, total int) {
+		fmt.Fprintf(os.Stderr, "\ranalysed %d/%d repositories", done, total)
+	})
+	fmt.Fprintln(os.Stderr)
+
+	digest := &service.Digest{
+		Lang:      lang,
+		Since:     sinceWindow,
+		CreatedAt: time.Now(),
+		Entries:   entries,
+	}
+	// a failed render must not leave half a digest behind
+	var b bytes.Buffer
+	if err := service.RenderDigest(&b, digest, format, templatePath); err != nil {
+		fmt.Fprintf(os.Stderr, "render error: %v\n", err)
+		os.Exit(1)
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(b.Bytes())
+	} else {
+		err = os.WriteFile(output, b.Bytes(), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "write output error: %v\n", err)
+		os.Exit(1)
+	}
+}
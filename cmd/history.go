@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"gitoday/storage"
+	"os"
+	"time"
+)
+
+// runHistory prints a recorded snapshot, without -at it lists when snapshots were taken.
+func runHistory(args []string) {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	var at, format string
+	trendingFlags(fs)
+	fs.StringVar(&at, "at", "", "Show the last snapshot taken at or before this time, 2006-01-02 or RFC3339")
+	fs.StringVar(&format, "format", formatTable, "The output format, table, json or csv")
+	configPath := configFlag(fs)
+	fs.Parse(args)
+
+	cfg := loadConfig(fs, *configPath)
+	lang, sinceWindow, spokenLanguage := trending(cfg)
+	initLogger("")
+	initStorage()
+	defer storage.Close()
+	store := storage.History()
+	if store == nil {
+		fmt.Fprintln(os.Stderr, "history is not available")
+		os.Exit(1)
+	}
+
+	if at == "" {
+		times, err := store.Times(lang, sinceWindow, spokenLanguage)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "read history error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, t := range times {
+			fmt.Println(t.Format(time.RFC3339))
+		}
+		return
+	}
+	t, err := parseAt(at)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -at %q: %v\n", at, err)
+		os.Exit(1)
+	}
+	snap, err := store.At(lang, sinceWindow, spokenLanguage, t)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "read history error: %v\n", err)
+		os.Exit(1)
+	}
+	if snap == nil {
+		fmt.Fprintf(os.Stderr, "no snapshot of %s %s before %s\n", lang, sinceWindow, t.Format(time.RFC3339))
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "snapshot taken at %s\n", snap.CreatedAt.Format(time.RFC3339))
+	if err := writeRepos(os.Stdout, snap.Repos(), format); err != nil {
+		fmt.Fprintf(os.Stderr, "write error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseAt reads a day as the end of that day, so "last tuesday" includes tuesday's crawls
+func parseAt(at string) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", at, time.Local); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return time.Parse(time.RFC3339, at)
+}
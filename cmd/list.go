@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"gitoday/service"
+	"gitoday/storage"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+)
+
+// runList crawls github trending once and prints the repositories without the TUI,
+// so it works in scripts and cron jobs where there is no terminal.
+func runList(args []string) {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	var mode, format string
+	fs.StringVar(&mode, "mode", "", "The environment to be used")
+	fixtures := fixtureFlags(fs)
+	trendingFlags(fs)
+	fs.StringVar(&format, "format", formatTable, "The output format, table, json or csv")
+	configPath := configFlag(fs)
+	fs.Parse(args)
+
+	cfg := loadConfig(fs, *configPath)
+	lang, sinceWindow, spokenLanguage := trending(cfg)
+	initLogger(mode)
+	initGlobal(fixtures, spokenLanguage)
+	initStorage()
+	defer storage.Close()
+
+	repos, err := service.Crawl(lang, sinceWindow, spokenLanguage)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "crawl error: %v\n", err)
+		os.Exit(1)
+	}
+	storage.Record(lang, sinceWindow, spokenLanguage, repos)
+	if err := writeRepos(os.Stdout, repos, format); err != nil {
+		fmt.Fprintf(os.Stderr, "write error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func writeRepos(w io.Writer, repos []*service.Repo, format string) error {
+	switch format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(repos)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"rank", "name", "url", "lang", "star", "fork", "todayStar", "desc"})
+		for i, r := range repos {
+			cw.Write([]string{fmt.Sprint(i + 1), r.Name, r.Url, r.Lang, r.Star, r.Fork, r.TodayStar, r.Desc})
+		}
+		cw.Flush()
+		return cw.Error()
+	case formatTable, "":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "#\tNAME\tLANG\tSTARS\tFORKS\tTODAY\tURL")
+		for i, r := range repos {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", i+1, r.Name, r.Lang, r.Star, r.Fork, r.TodayStar, r.Url)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
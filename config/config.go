@@ -0,0 +1,201 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+type AI struct {
+	Provider string        `yaml:"provider"`
+	Endpoint string        `yaml:"endpoint"`
+	Model    string        `yaml:"model"`
+	APIKey   string        `yaml:"api_key"`
+	Timeout  time.Duration `yaml:"timeout"`
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+	// Prompt is a text/template file replacing the built-in prompt
+	Prompt string `yaml:"prompt"`
+	// Concurrency is how many analyses run at the same time
+	Concurrency int `yaml:"concurrency"`
+}
+
+// Config is read from the config file, then env vars and flags override it.
+type Config struct {
+	Language string `yaml:"language"`
+	Since    string `yaml:"since"`
+	Spoken   string `yaml:"spoken"`
+	Theme    string `yaml:"theme"`
+	// Locale is the language of the AI answers and the TUI, en or zh
+	Locale       string        `yaml:"locale"`
+	CrawlTimeout time.Duration `yaml:"crawl_timeout"`
+	// Enrich adds topics, license and activity from the GitHub REST API to the crawled repos
+	Enrich bool `yaml:"enrich"`
+	AI     AI   `yaml:"ai"`
+	// Keys maps a repo view action to comma separated keys, e.g. open: "o,ctrl+o"
+	Keys map[string]string `yaml:"keys"`
+}
+
+func Default() Config {
+	return Config{
+		Language:     "all",
+		Since:        "daily",
+		Theme:        "default",
+		Locale:       "en",
+		CrawlTimeout: 30 * time.Second,
+		AI: AI{
+			Provider:    "dify",
+			Timeout:     200 * time.Second,
+			CacheTTL:    7 * 24 * time.Hour,
+			Concurrency: 3,
+		},
+	}
+}
+
+// DefaultPath is $XDG_CONFIG_HOME/gitoday/config.yaml, ~/.config is used when it is unset.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "gitoday", "config.yaml"), nil
+}
+
+// Load reads the config file over the defaults, the file has to exist.
+func Load(path string) (Config, error) {
+	c := Default()
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return c, errors.Wrap(err, "read config error")
+	}
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return c, errors.Wrapf(err, "parse config %s error", path)
+	}
+	return c, nil
+}
+
+// LoadOptional is Load for the default path, a missing file gives the defaults.
+func LoadOptional(path string) (Config, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return Default(), nil
+	}
+	return Load(path)
+}
+
+// envs maps the env vars to the settings they override
+var envs = map[string]string{
+	"AI_PROVIDER":    "provider",
+	"AI_ENDPOINT":    "endpoint",
+	"AI_MODEL":       "model",
+	"API_KEY":        "api-key",
+	"AI_TIMEOUT":     "ai-timeout",
+	"GITODAY_LANG":   "lang",
+	"GITODAY_SINCE":  "since",
+	"GITODAY_SPOKEN": "spoken",
+	"GITODAY_THEME":  "theme",
+	"GITODAY_LOCALE": "locale",
+	"CRAWL_TIMEOUT":  "crawl-timeout",
+	"GITODAY_ENRICH": "enrich",
+	"AI_CACHE_TTL":   "cache-ttl",
+	"AI_CONCURRENCY": "concurrency",
+	"AI_PROMPT":      "prompt",
+}
+
+// ApplyEnv overrides the settings whose env var is set.
+func (c *Config) ApplyEnv() error {
+	for env, name := range envs {
+		if v, ok := os.LookupEnv(env); ok && v != "" {
+			if err := c.Set(name, v); err != nil {
+				return errors.Wrapf(err, "env %s", env)
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyFlags overrides the settings whose flag is set on the command line,
+// flags that are not settings are left alone.
+func (c *Config) ApplyFlags(fs *flag.FlagSet) error {
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if err != nil || !isSetting(f.Name) {
+			return
+		}
+		if e := c.Set(f.Name, f.Value.String()); e != nil {
+			err = errors.Wrapf(e, "flag -%s", f.Name)
+		}
+	})
+	return err
+}
+
+func isSetting(name string) bool {
+	for _, v := range envs {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Set overrides a setting by the name of its flag.
+func (c *Config) Set(name, value string) error {
+	var err error
+	switch name {
+	case "lang":
+		c.Language = value
+	case "since":
+		c.Since = value
+	case "spoken":
+		c.Spoken = value
+	case "theme":
+		c.Theme = value
+	case "locale":
+		c.Locale = value
+	case "crawl-timeout":
+		c.CrawlTimeout, err = time.ParseDuration(value)
+	case "enrich":
+		c.Enrich, err = strconv.ParseBool(value)
+	case "provider":
+		c.AI.Provider = value
+	case "endpoint":
+		c.AI.Endpoint = value
+	case "model":
+		c.AI.Model = value
+	case "api-key":
+		c.AI.APIKey = value
+	case "ai-timeout":
+		c.AI.Timeout, err = time.ParseDuration(value)
+	case "cache-ttl":
+		c.AI.CacheTTL, err = time.ParseDuration(value)
+	case "prompt":
+		c.AI.Prompt = value
+	case "concurrency":
+		c.AI.Concurrency, err = strconv.Atoi(value)
+	default:
+		return fmt.Errorf("unknown setting %q", name)
+	}
+	return err
+}
+
+// Bindings splits Keys into the keys of every action.
+func (c *Config) Bindings() map[string][]string {
+	bindings := make(map[string][]string, len(c.Keys))
+	for action, keys := range c.Keys {
+		for _, k := range strings.Split(keys, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				bindings[action] = append(bindings[action], k)
+			}
+		}
+	}
+	return bindings
+}
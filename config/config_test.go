@@ -0,0 +1,85 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	c, err := LoadOptional(missing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Language != "all" || c.AI.Timeout != 200*time.Second {
+		t.Errorf("expected defaults for a missing file, got %+v", c)
+	}
+	// a file given explicitly has to be read
+	if _, err := Load(missing); err == nil {
+		t.Error("expected a missing config file to fail")
+	}
+	if _, err := LoadOptional(t.TempDir()); err == nil {
+		t.Error("expected a config file that cannot be read to fail")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	err = os.WriteFile(path, []byte(`
+language: go
+since: weekly
+crawl_timeout: 10s
+ai:
+  provider: openai
+  endpoint: http://localhost:11434/v1
+  cache_ttl: 24h
+keys:
+  open: "o, ctrl+o"
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Language != "go" || c.Since != "weekly" || c.CrawlTimeout != 10*time.Second {
+		t.Errorf("unexpected config %+v", c)
+	}
+	// unset values keep their default
+	if c.AI.Provider != "openai" || c.AI.CacheTTL != 24*time.Hour || c.AI.Timeout != 200*time.Second {
+		t.Errorf("unexpected ai config %+v", c.AI)
+	}
+	if keys := c.Bindings()["open"]; len(keys) != 2 || keys[1] != "ctrl+o" {
+		t.Errorf("unexpected open keys %v", keys)
+	}
+}
+
+func TestPrecedence(t *testing.T) {
+	c := Default()
+	c.AI.Provider = "openai"
+	c.AI.Model = "from-file"
+	t.Setenv("AI_MODEL", "from-env")
+	t.Setenv("GITODAY_SINCE", "monthly")
+	if err := c.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("since", "daily", "")
+	fs.String("model", "", "")
+	fs.Bool("preview", false, "")
+	if err := fs.Parse([]string{"-since=weekly", "-preview"}); err != nil {
+		t.Fatal(err)
+	}
+	// only the flags set on the command line override, -model keeps the env value
+	if err := c.ApplyFlags(fs); err != nil {
+		t.Fatal(err)
+	}
+	if c.AI.Provider != "openai" || c.AI.Model != "from-env" || c.Since != "weekly" {
+		t.Errorf("unexpected config %+v", c)
+	}
+	if err := c.Set("cache-ttl", "soon"); err == nil {
+		t.Error("expected an invalid duration to fail")
+	}
+}
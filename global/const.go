@@ -24,7 +24,68 @@ const (
 	Scala  Language = "scala"
 )
 
+type Since string
+
+const (
+	Daily   Since = "daily"
+	Weekly  Since = "weekly"
+	Monthly Since = "monthly"
+)
+
+// SpokenLanguage is the spoken_language_code accepted by github trending,
+// the empty value means any spoken language.
+type SpokenLanguage string
+
+const (
+	AnySpoken SpokenLanguage = ""
+	English   SpokenLanguage = "en"
+	Chinese   SpokenLanguage = "zh"
+	Japanese  SpokenLanguage = "ja"
+	Korean    SpokenLanguage = "ko"
+	Spanish   SpokenLanguage = "es"
+	French    SpokenLanguage = "fr"
+	German    SpokenLanguage = "de"
+	Russian   SpokenLanguage = "ru"
+)
+
+var SpokenLanguages = []SpokenLanguage{AnySpoken, English, Chinese, Japanese, Korean, Spanish, French, German, Russian}
+
+// Locale is the language of the AI answers and the TUI
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocaleChinese Locale = "zh"
+)
+
+var Locales = []Locale{LocaleEnglish, LocaleChinese}
+
+// Name is the english name of the language, it is how the prompt asks for it.
+func (l Locale) Name() string {
+	if l == LocaleChinese {
+		return "Simplified Chinese"
+	}
+	return "English"
+}
+
+// ParseLocale reads a locale code, empty means English.
+func ParseLocale(code string) (Locale, bool) {
+	if code == "" {
+		return LocaleEnglish, true
+	}
+	for _, l := range Locales {
+		if string(l) == code {
+			return l, true
+		}
+	}
+	return LocaleEnglish, false
+}
+
 var isPreview bool
+var locale = LocaleEnglish
+var language = All
+var since = Daily
+var spokenLanguage SpokenLanguage
 
 func SetPreview(p bool) {
 	isPreview = p
@@ -32,6 +93,38 @@ func SetPreview(p bool) {
 func IsPreviewMode() bool {
 	return isPreview
 }
+
+// SetLocale sets the language of the AI answers and the TUI.
+func SetLocale(l Locale) {
+	locale = l
+}
+func CurrentLocale() Locale {
+	return locale
+}
+
+// SetLanguage sets the code language picked by default in the fetch view.
+func SetLanguage(l Language) {
+	language = l
+}
+func DefaultLanguage() Language {
+	return language
+}
+
+// SetSince sets the trending window picked by default in the fetch view.
+func SetSince(s Since) {
+	since = s
+}
+func DefaultSince() Since {
+	return since
+}
+
+// SetSpokenLanguage sets the spoken language picked by default in the fetch view.
+func SetSpokenLanguage(s SpokenLanguage) {
+	spokenLanguage = s
+}
+func DefaultSpokenLanguage() SpokenLanguage {
+	return spokenLanguage
+}
 func (l Language) LanguageType() LanguageType {
 	switch l {
 	case All:
@@ -52,3 +145,76 @@ func (t LanguageType) Language() Language {
 		return "All"
 	}
 }
+
+// Languages are the programming languages gitoday offers, in the order the TUI lists them.
+var Languages = []Language{All, GoLang, Java, JS, TS, Python, Ruby, PHP, Swift, Kotlin, Rust, Scala}
+
+func ParseLanguage(s string) (Language, bool) {
+	if s == "" {
+		return All, true
+	}
+	for _, l := range Languages {
+		if string(l) == s {
+			return l, true
+		}
+	}
+	return All, false
+}
+
+func ParseSince(s string) (Since, bool) {
+	switch Since(s) {
+	case Daily, Weekly, Monthly:
+		return Since(s), true
+	case "":
+		return Daily, true
+	default:
+		return Daily, false
+	}
+}
+
+// Label returns the english period of the trending window, the digest templates
+// print it as {{.Since.Label}}. The TUI uses its own localized labels.
+func (s Since) Label() string {
+	switch s {
+	case Weekly:
+		return "this week"
+	case Monthly:
+		return "this month"
+	default:
+		return "today"
+	}
+}
+
+func (s SpokenLanguage) Label() string {
+	switch s {
+	case AnySpoken:
+		return "any"
+	case English:
+		return "English"
+	case Chinese:
+		return "Chinese"
+	case Japanese:
+		return "Japanese"
+	case Korean:
+		return "Korean"
+	case Spanish:
+		return "Spanish"
+	case French:
+		return "French"
+	case German:
+		return "German"
+	case Russian:
+		return "Russian"
+	default:
+		return string(s)
+	}
+}
+
+func ParseSpokenLanguage(code string) (SpokenLanguage, bool) {
+	for _, s := range SpokenLanguages {
+		if string(s) == code {
+			return s, true
+		}
+	}
+	return AnySpoken, false
+}
@@ -2,13 +2,23 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
 )
 
 // test ai chat
 func TestChat(t *testing.T) {
-	l, err := Chat(context.Background(), "https://www.github.com/pocketbase/pocketbase")
+	key := os.Getenv("API_KEY")
+	if key == "" {
+		t.Skip("API_KEY is not set")
+	}
+	Init(NewDifyProvider(difyEndpoint, key))
+	l, err := Chat(context.Background(), "https://www.github.com/pocketbase/pocketbase", 3)
 	if err != nil {
 		t.Error(err)
 		return
@@ -26,3 +36,59 @@ func TestChat(t *testing.T) {
 	}
 	fmt.Printf("%+v", l.Other)
 }
+
+func TestOpenAIProvider(t *testing.T) {
+	stubGitHub(t, "pocketbase README")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req openAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		if req.Model != "local-model" || len(req.Messages) != 1 {
+			t.Errorf("unexpected request %+v", req)
+		}
+		if !strings.Contains(req.Messages[0].Content, "pocketbase README") {
+			t.Error("README is not in the prompt")
+		}
+		fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"{\"what\":\"a tool\",\"why\":[\"fast\"],\"how\":[\"go\"]}"}}]}`)
+	}))
+	defer server.Close()
+
+	Init(NewOpenAIProvider(server.URL+"/v1/", "", "local-model"))
+	l, err := Chat(context.Background(), "https://www.github.com/o/n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.What != "a tool" || len(l.Why) != 1 {
+		t.Errorf("unexpected response %+v", *l)
+	}
+}
+
+func TestChatStream(t *testing.T) {
+	stubGitHub(t, "")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, token := range []string{`{\"what\":`, `\"a tool\",`, `\"why\":[\"fast\"],\"how\":[\"go\"]}`} {
+			fmt.Fprintf(w, "data: {\"event\":\"message\",\"answer\":\"%s\"}\n\n", token)
+		}
+		fmt.Fprint(w, "data: {\"event\":\"message_end\"}\n\n")
+	}))
+	defer server.Close()
+
+	Init(NewDifyProvider(server.URL, "key"))
+	var partials []string
+	l, err := ChatStream(context.Background(), "https://www.github.com/pocketbase/pocketbase", 0, func(answer string) {
+		partials = append(partials, answer)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(partials) != 3 || partials[0] != `{"what":` || partials[2] != `{"what":"a tool","why":["fast"],"how":["go"]}` {
+		t.Errorf("unexpected partial answers %q", partials)
+	}
+	if l.What != "a tool" || len(l.Why) != 1 {
+		t.Errorf("unexpected response %+v", *l)
+	}
+}
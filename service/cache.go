@@ -0,0 +1,117 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"gitoday/global"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cacheEntry is one analysis persisted on disk, the file name is derived from
+// the repo url, the prompt version and the answer language so a new prompt never reads stale answers.
+type cacheEntry struct {
+	Url           string        `json:"url"`
+	PromptVersion string        `json:"promptVersion"`
+	Locale        global.Locale `json:"locale"`
+	CreatedAt     time.Time     `json:"createdAt"`
+	Response      *ChatResponse `json:"response"`
+}
+
+// InitCache enables the analysis cache of the default Client under dir, a non positive ttl disables it.
+func InitCache(dir string, ttl time.Duration) {
+	defaultClient.InitCache(dir, ttl)
+}
+
+// InitCache enables the analysis cache under dir, a non positive ttl disables it.
+func (c *Client) InitCache(dir string, ttl time.Duration) {
+	c.cacheDir = dir
+	c.cacheTTL = ttl
+}
+
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gitoday", "analyses"), nil
+}
+
+func (c *Client) cacheEnabled() bool {
+	// replayed answers are not worth caching and cached ones would never be recorded
+	return c.cacheDir != "" && c.cacheTTL > 0 && c.fixtures() == nil && !c.recording()
+}
+
+func (c *Client) cachePath(repoUrl string) string {
+	sum := sha256.Sum256([]byte(c.prompt.version + "\n" + string(global.CurrentLocale()) + "\n" + repoUrl))
+	return filepath.Join(c.cacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+// LoadAnalysis returns the cached analysis of the repo from the default Client.
+func LoadAnalysis(repoUrl string) (*ChatResponse, bool) {
+	return defaultClient.LoadAnalysis(repoUrl)
+}
+
+// LoadAnalysis returns the cached analysis of the repo if it is not expired.
+func (c *Client) LoadAnalysis(repoUrl string) (*ChatResponse, bool) {
+	if !c.cacheEnabled() {
+		return nil, false
+	}
+	b, err := os.ReadFile(c.cachePath(repoUrl))
+	if err != nil {
+		return nil, false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil || e.Response == nil {
+		return nil, false
+	}
+	if e.Url != repoUrl || e.PromptVersion != c.prompt.version || e.Locale != global.CurrentLocale() || c.Now().Sub(e.CreatedAt) > c.cacheTTL {
+		return nil, false
+	}
+	return e.Response, true
+}
+
+// SaveAnalysis writes the analysis of the repo to the cache of the default Client.
+func SaveAnalysis(repoUrl string, cr *ChatResponse) error {
+	return defaultClient.SaveAnalysis(repoUrl, cr)
+}
+
+// SaveAnalysis writes the analysis of the repo to the cache.
+func (c *Client) SaveAnalysis(repoUrl string, cr *ChatResponse) error {
+	if !c.cacheEnabled() || cr == nil || cr.Error != nil {
+		return nil
+	}
+	if err := os.MkdirAll(c.cacheDir, 0o755); err != nil {
+		return errors.Wrap(err, "create cache dir error")
+	}
+	b, err := json.Marshal(cacheEntry{
+		Url:           repoUrl,
+		PromptVersion: c.prompt.version,
+		Locale:        global.CurrentLocale(),
+		CreatedAt:     c.Now(),
+		Response:      cr,
+	})
+	if err != nil {
+		return errors.Wrap(err, "json marshal error")
+	}
+	// write to a temp file first so a concurrent reader never sees half an entry
+	path := c.cachePath(repoUrl)
+	tmp, err := os.CreateTemp(c.cacheDir, ".entry-*")
+	if err != nil {
+		return errors.Wrap(err, "create cache file error")
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return errors.Wrap(err, "write cache file error")
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return errors.Wrap(err, "write cache file error")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), path), "rename cache file error")
+}
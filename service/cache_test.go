@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnalysisCache(t *testing.T) {
+	now := time.Now()
+	c := NewClient(nil)
+	c.Now = func() time.Time { return now }
+	c.InitCache(t.TempDir(), time.Hour)
+
+	url := "https://www.github.com/pocketbase/pocketbase"
+	if _, ok := c.LoadAnalysis(url); ok {
+		t.Fatal("expected empty cache")
+	}
+	if err := c.SaveAnalysis(url, &ChatResponse{What: "backend", Why: []string{"simple"}}); err != nil {
+		t.Fatal(err)
+	}
+	cr, ok := c.LoadAnalysis(url)
+	if !ok || cr.What != "backend" || len(cr.Why) != 1 {
+		t.Fatalf("unexpected cached analysis %+v %v", cr, ok)
+	}
+	if _, ok := c.LoadAnalysis("https://www.github.com/other/repo"); ok {
+		t.Error("unexpected hit for another repo")
+	}
+
+	now = now.Add(time.Hour + time.Second)
+	if _, ok := c.LoadAnalysis(url); ok {
+		t.Error("expected expired entry to miss")
+	}
+}
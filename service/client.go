@@ -0,0 +1,120 @@
+package service
+
+import (
+	"io/fs"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	trendingURL  = "https://github.com/trending"
+	githubAPIURL = "https://api.github.com"
+)
+
+// Client is how the service package reaches GitHub and the AI provider, the package
+// level functions use the default one. Tests point a Client at an httptest server.
+type Client struct {
+	// HTTP crawls the trending pages and calls the GitHub API, its Timeout bounds each request
+	HTTP *http.Client
+	// AIHTTP is used by the providers NewProvider builds, the Chat context bounds those requests
+	AIHTTP *http.Client
+	// TrendingURL is the trending page, the developers page is below it
+	TrendingURL string
+	GitHubAPI   string
+	// Now and After are the clock of the rate limit, the retry backoff and the analysis cache
+	Now   func() time.Time
+	After func(d time.Duration) <-chan time.Time
+
+	provider  Provider
+	aiTimeout time.Duration
+	retry     retryPolicy
+	prompt    promptTemplate
+	// enrichCrawls adds the GitHub API metadata to every crawl
+	enrichCrawls bool
+	// cacheDir keeps the AI analyses for cacheTTL, see InitCache
+	cacheDir string
+	cacheTTL time.Duration
+	// githubCacheDir keeps the ETag and body of GitHub API responses, a 304 answer
+	// to a conditional request does not count against the rate limit.
+	githubCacheDir string
+	// rateLimit is when the GitHub API accepts requests again after the limit was hit
+	rateLimit struct {
+		sync.Mutex
+		until time.Time
+	}
+	// repos are the repositories the GitHub API described, by full name. The analysis
+	// reuses what the enrichment of the crawl fetched.
+	repos struct {
+		sync.Mutex
+		byName map[string]*githubRepo
+	}
+	// recordDir and replayFS are set by Record and Replay
+	recordDir string
+	replayFS  fs.FS
+}
+
+// NewClient builds a Client talking to github.com through httpClient,
+// a nil httpClient gets a 30 seconds timeout.
+func NewClient(httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		HTTP:        httpClient,
+		AIHTTP:      &http.Client{},
+		TrendingURL: trendingURL,
+		GitHubAPI:   githubAPIURL,
+		Now:         time.Now,
+		After:       time.After,
+		aiTimeout:   200 * time.Second,
+		retry:       defaultRetry,
+		prompt:      builtinPrompt,
+	}
+}
+
+var defaultClient = NewClient(nil)
+
+// DefaultClient is the Client behind the package level functions.
+func DefaultClient() *Client {
+	return defaultClient
+}
+
+// SetClient replaces the Client behind the package level functions.
+func SetClient(c *Client) {
+	defaultClient = c
+}
+
+// SetProvider sets the AI backend Chat asks.
+func (c *Client) SetProvider(p Provider) {
+	c.provider = p
+}
+
+// SetTimeouts sets how long crawling a page and asking AI may take, non positive values are ignored.
+func (c *Client) SetTimeouts(crawl, ai time.Duration) {
+	if crawl > 0 {
+		c.HTTP.Timeout = crawl
+	}
+	if ai > 0 {
+		c.aiTimeout = ai
+	}
+}
+
+// InitGitHubCache enables the ETag cache of GitHub API responses under dir.
+func (c *Client) InitGitHubCache(dir string) {
+	c.githubCacheDir = dir
+}
+
+func Init(p Provider) {
+	defaultClient.SetProvider(p)
+}
+
+// SetTimeouts sets the timeouts of the default Client.
+func SetTimeouts(crawl, ai time.Duration) {
+	defaultClient.SetTimeouts(crawl, ai)
+}
+
+// InitGitHubCache enables the ETag cache of the default Client under dir.
+func InitGitHubCache(dir string) {
+	defaultClient.InitGitHubCache(dir)
+}
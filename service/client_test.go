@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestClient makes a Client talking to handler the default one until the test ends,
+// it retries without noticeable backoff.
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	c := NewClient(server.Client())
+	c.AIHTTP = server.Client()
+	c.TrendingURL = server.URL + "/trending"
+	c.GitHubAPI = server.URL
+	c.retry = retryPolicy{attempts: 3, base: time.Millisecond, max: 50 * time.Millisecond}
+	old := defaultClient
+	SetClient(c)
+	t.Cleanup(func() {
+		SetClient(old)
+		server.Close()
+	})
+	return c
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestClientNewProvider(t *testing.T) {
+	var got string
+	c := NewClient(nil)
+	c.AIHTTP = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		got = r.URL.String()
+		body := `{"choices":[{"message":{"role":"assistant","content":"hi"}}]}`
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+	p, err := c.NewProvider(ProviderOpenAI, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err := p.Ask(context.Background(), "hello")
+	if err != nil || answer != "hi" {
+		t.Fatalf("got %q, %v", answer, err)
+	}
+	if got != openAIEndpoint+"/chat/completions" {
+		t.Errorf("request sent to %s", got)
+	}
+	if _, err := c.NewProvider("unknown", "", "", ""); err == nil {
+		t.Error("expected an unknown provider to fail")
+	}
+}
@@ -2,36 +2,42 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"gitoday/global"
 	"io"
+	"math"
 	"net/http"
-	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/pkg/errors"
 )
 
-var path = "https://github.com/trending"
-
 type Repo struct {
-	Name      string
-	Url       string
-	Desc      string
-	Lang      string
-	Star      string
-	Fork      string
-	TodayStar string
+	Name      string `json:"name"`
+	Url       string `json:"url"`
+	Desc      string `json:"desc"`
+	Lang      string `json:"lang"`
+	Star      string `json:"star"`
+	Fork      string `json:"fork"`
+	TodayStar string `json:"todayStar"`
+	// the counts parsed from Star, Fork and TodayStar, which keep the text for display
+	StarCount      int `json:"starCount"`
+	ForkCount      int `json:"forkCount"`
+	TodayStarCount int `json:"todayStarCount"`
+	// Meta is only set when the GitHub API enrichment is on
+	Meta *RepoMeta `json:"meta,omitempty"`
 }
 
-func Crawl(lang global.Language) ([]*Repo, error) {
-	url := path
-	if lang != global.All {
-		url = fmt.Sprintf("%s/%s?since=daily", path, lang)
-	}
-	body, err := fetch(url)
+func Crawl(lang global.Language, since global.Since, spoken global.SpokenLanguage) ([]*Repo, error) {
+	return defaultClient.Crawl(lang, since, spoken)
+}
+
+// Crawl reads the trending repositories, enriched from the GitHub API when SetEnrich is on.
+func (c *Client) Crawl(lang global.Language, since global.Since, spoken global.SpokenLanguage) ([]*Repo, error) {
+	body, err := c.fetch(c.trendingUrl(lang, since, spoken))
 	if err != nil {
 		err := errors.Wrap(err, "fetch error")
 		return nil, err
@@ -41,9 +47,25 @@ func Crawl(lang global.Language) ([]*Repo, error) {
 		err := errors.Wrap(err, "parse error")
 		return nil, err
 	}
+	c.enrich(res)
 	return res, nil
 }
 
+func (c *Client) trendingUrl(lang global.Language, since global.Since, spoken global.SpokenLanguage) string {
+	url := c.TrendingURL
+	if lang != global.All {
+		url = fmt.Sprintf("%s/%s", c.TrendingURL, lang)
+	}
+	if since == "" {
+		since = global.Daily
+	}
+	url = fmt.Sprintf("%s?since=%s", url, since)
+	if spoken != global.AnySpoken {
+		url = fmt.Sprintf("%s&spoken_language_code=%s", url, spoken)
+	}
+	return url
+}
+
 func parse(body []byte) ([]*Repo, error) {
 	buf := bytes.NewBuffer(body)
 	doc, err := goquery.NewDocumentFromReader(buf)
@@ -71,42 +93,54 @@ func parse(body []byte) ([]*Repo, error) {
 	return repoList, nil
 }
 
-func fetch(url string) ([]byte, error) {
-	var read io.Reader
-	if global.IsPreviewMode() {
-		wd, err := os.Getwd()
+func (c *Client) fetch(url string) ([]byte, error) {
+	var body []byte
+	err := c.withRetry(context.Background(), c.retry, func() error {
+		req, err := http.NewRequest("GET", url, nil)
 		if err != nil {
-			return nil, err
+			return errors.Wrap(err, "create http request error")
 		}
-		//read from a file path
-		filePath := filepath.Join(wd, "service", "debug.html")
-		r, err := os.Open(filePath)
+		resp, err := c.do(req)
 		if err != nil {
-			return nil, err
-		}
-		defer r.Close()
-		read = r
-	} else {
-		resp, err := http.Get(url)
-		if err != nil {
-			return nil, err
+			return err
 		}
 		defer resp.Body.Close()
 		if resp.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("status code is not 200")
+			return newStatusError(resp, "", c.Now())
 		}
-		read = resp.Body
-	}
-
-	body, err := io.ReadAll(read)
-	if err != nil {
-		return nil, err
-	}
-	return body, nil
+		body, err = io.ReadAll(resp.Body)
+		return err
+	})
+	return body, err
 }
 func (r *Repo) format() {
 	r.Desc = strings.TrimSpace(strings.ReplaceAll(r.Desc, "\n", ""))
 	r.Star = strings.TrimSpace(strings.ReplaceAll(r.Star, "\n", ""))
 	r.Fork = strings.TrimSpace(strings.ReplaceAll(r.Fork, "\n", ""))
 	r.TodayStar = strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(r.TodayStar, "stars today", ""), "\n", ""))
+	// weekly and monthly windows say "stars this week" or "stars this month"
+	r.TodayStar = strings.TrimSpace(strings.TrimSuffix(strings.TrimSuffix(r.TodayStar, "stars this week"), "stars this month"))
+	r.StarCount = ParseCount(r.Star)
+	r.ForkCount = ParseCount(r.Fork)
+	r.TodayStarCount = ParseCount(r.TodayStar)
+}
+
+// ParseCount reads counts github renders like "12,345", "1.2k" or "3m",
+// anything else is 0.
+func ParseCount(s string) int {
+	s = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), ",", ""))
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"):
+		multiplier = 1e3
+		s = strings.TrimSuffix(s, "k")
+	case strings.HasSuffix(s, "m"):
+		multiplier = 1e6
+		s = strings.TrimSuffix(s, "m")
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return int(math.Round(n * multiplier))
 }
@@ -1,17 +1,193 @@
 package service
 
 import (
-	"gitrender/global"
+	"errors"
+	"gitoday/global"
+	"net/http"
+	"os"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
+// serveFile answers requests to path with a saved page and 404 otherwise
+func serveFile(t *testing.T, path, query, file string) http.Handler {
+	body, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path || r.URL.RawQuery != query {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(body)
+	})
+}
+
 func TestCrawl(t *testing.T) {
-	global.Mode = "debug"
-	res, err := Crawl(global.GoLang)
+	newTestClient(t, serveFile(t, "/trending/go", "since=weekly&spoken_language_code=en", "fixtures/http/github.com_trending"))
+	res, err := Crawl(global.GoLang, global.Weekly, global.English)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 19 || res[0].Name != "goldmansachs/gs-quant" || res[0].Url != "https://www.github.com/goldmansachs/gs-quant" {
+		t.Errorf("got %d repos, first %+v", len(res), res[0])
+	}
+	_, err = Crawl(global.Rust, global.Daily, global.AnySpoken)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("error %v is not a 404", err)
+	}
+}
+
+func TestCrawlDevelopers(t *testing.T) {
+	newTestClient(t, serveFile(t, "/trending/developers", "since=daily", "fixtures/http/github.com_trending_developers"))
+	res, err := CrawlDevelopers(global.All, global.Daily)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 5 || res[0].Handle != "spf13" {
+		t.Errorf("got %d developers, first %+v", len(res), res[0])
+	}
+}
+
+func TestCrawlTimeout(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer close(release)
+	c.SetTimeouts(20*time.Millisecond, 0)
+
+	_, err := Crawl(global.All, global.Daily, global.AnySpoken)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("error %v is not a timeout", err)
+	}
+	// the request already waited the whole timeout, asking again would only double it
+	if n := calls.Load(); n != 1 {
+		t.Errorf("%d calls, want 1 as timeouts are not retried", n)
+	}
+}
+
+func TestTrendingUrl(t *testing.T) {
+	cases := []struct {
+		lang   global.Language
+		since  global.Since
+		spoken global.SpokenLanguage
+		want   string
+	}{
+		{global.All, global.Daily, global.AnySpoken, "https://github.com/trending?since=daily"},
+		{global.All, global.Weekly, global.AnySpoken, "https://github.com/trending?since=weekly"},
+		{global.GoLang, global.Monthly, global.AnySpoken, "https://github.com/trending/go?since=monthly"},
+		{global.Rust, "", global.AnySpoken, "https://github.com/trending/rust?since=daily"},
+		{global.All, global.Daily, global.Chinese, "https://github.com/trending?since=daily&spoken_language_code=zh"},
+		{global.GoLang, global.Weekly, global.English, "https://github.com/trending/go?since=weekly&spoken_language_code=en"},
+	}
+	client := NewClient(nil)
+	for _, c := range cases {
+		if got := client.trendingUrl(c.lang, c.since, c.spoken); got != c.want {
+			t.Errorf("trendingUrl(%s, %s, %s) = %s, want %s", c.lang, c.since, c.spoken, got, c.want)
+		}
+	}
+}
+
+func TestParseDevelopers(t *testing.T) {
+	body, err := os.ReadFile("fixtures/http/github.com_trending_developers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := parseDevelopers(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 5 {
+		t.Fatalf("got %d developers, want 5", len(res))
+	}
+	first := res[0]
+	if first.Handle != "spf13" || first.Name != "Steve Francia" || first.Repo != "cobra" {
+		t.Errorf("unexpected first developer %+v", *first)
+	}
+	if first.RepoUrl != "https://www.github.com/spf13/cobra" {
+		t.Errorf("unexpected repo url %s", first.RepoUrl)
+	}
+	// developers without a display name only render their handle
+	if res[1].Handle != "charmbracelet" || res[1].Name != "charmbracelet" {
+		t.Errorf("unexpected second developer %+v", *res[1])
+	}
+	if res[4].Repo != "" {
+		t.Errorf("expected no popular repo, got %s", res[4].Repo)
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int
+	}{
+		{"0", 0},
+		{"617", 617},
+		{"12,345", 12345},
+		{" 1,234,567 \n", 1234567},
+		{"1.2k", 1200},
+		{"12k", 12000},
+		{"3.5K", 3500},
+		{"1.1m", 1100000},
+		{"", 0},
+		{"n/a", 0},
+		{"-3", 0},
+	}
+	for _, c := range cases {
+		if got := ParseCount(c.in); got != c.want {
+			t.Errorf("ParseCount(%q) = %d, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseCounts(t *testing.T) {
+	body, err := os.ReadFile("fixtures/http/github.com_trending")
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
+	}
+	res, err := parse(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		index     int
+		name      string
+		star      string
+		starCount int
+		forkCount int
+		today     string
+		todayStar int
+	}{
+		{0, "goldmansachs/gs-quant", "4,392", 4392, 617, "318", 318},
+		{1, "WerWolv/ImHex", "37,750", 37750, 1659, "1,168", 1168},
+		{4, "neovim/neovim", "79,655", 79655, 5487, "92", 92},
+		{18, "hajimehoshi/ebiten", "10,239", 10239, 632, "15", 15},
+	}
+	if len(res) < 19 {
+		t.Fatalf("got %d repos, want at least 19", len(res))
+	}
+	for _, c := range cases {
+		r := res[c.index]
+		if r.Name != c.name || r.Star != c.star || r.TodayStar != c.today {
+			t.Errorf("repo %d = %s %s %s, want %s %s %s", c.index, r.Name, r.Star, r.TodayStar, c.name, c.star, c.today)
+		}
+		if r.StarCount != c.starCount || r.ForkCount != c.forkCount || r.TodayStarCount != c.todayStar {
+			t.Errorf("repo %s counts = %d %d %d, want %d %d %d", r.Name, r.StarCount, r.ForkCount, r.TodayStarCount,
+				c.starCount, c.forkCount, c.todayStar)
+		}
 	}
 	for _, r := range res {
-		t.Logf("%+v", *r)
+		if r.Star != "" && r.StarCount == 0 {
+			t.Errorf("repo %s star %q was not parsed", r.Name, r.Star)
+		}
 	}
 }
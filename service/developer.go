@@ -0,0 +1,90 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"gitoday/global"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/pkg/errors"
+)
+
+type Developer struct {
+	Handle   string
+	Name     string
+	Url      string
+	Repo     string
+	RepoUrl  string
+	RepoDesc string
+}
+
+func CrawlDevelopers(lang global.Language, since global.Since) ([]*Developer, error) {
+	return defaultClient.CrawlDevelopers(lang, since)
+}
+
+// CrawlDevelopers reads the trending developers.
+func (c *Client) CrawlDevelopers(lang global.Language, since global.Since) ([]*Developer, error) {
+	body, err := c.fetch(c.developersUrl(lang, since))
+	if err != nil {
+		err := errors.Wrap(err, "fetch error")
+		return nil, err
+	}
+	res, err := parseDevelopers(body)
+	if err != nil {
+		err := errors.Wrap(err, "parse error")
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) developersUrl(lang global.Language, since global.Since) string {
+	url := c.TrendingURL + "/developers"
+	if lang != global.All {
+		url = fmt.Sprintf("%s/%s", url, lang)
+	}
+	if since == "" {
+		since = global.Daily
+	}
+	return fmt.Sprintf("%s?since=%s", url, since)
+}
+
+func parseDevelopers(body []byte) ([]*Developer, error) {
+	buf := bytes.NewBuffer(body)
+	doc, err := goquery.NewDocumentFromReader(buf)
+	if err != nil {
+		return nil, err
+	}
+	developerList := make([]*Developer, 0)
+	doc.Find("article.Box-row").Each(func(i int, s *goquery.Selection) {
+		developer := &Developer{}
+		title := s.Find("h1.h3.lh-condensed").Find("a")
+		href, ok := title.Attr("href")
+		if !ok {
+			return
+		}
+		developer.Handle = strings.TrimPrefix(href, "/")
+		developer.Name = title.Text()
+		// the handle is only rendered separately when the developer has a display name
+		if handle := s.Find("p.f4.text-normal.mb-1").Find("a").Text(); strings.TrimSpace(handle) != "" {
+			developer.Handle = handle
+		}
+		developer.Url = "https://www.github.com" + href
+		popular := s.Find("h1.h4.lh-condensed").Find("a")
+		if repoHref, ok := popular.Attr("href"); ok {
+			developer.Repo = popular.Text()
+			developer.RepoUrl = "https://www.github.com" + repoHref
+			developer.RepoDesc = s.Find("div.f6.color-fg-muted.mt-1").Text()
+		}
+		developer.format()
+		developerList = append(developerList, developer)
+	})
+	return developerList, nil
+}
+
+func (d *Developer) format() {
+	d.Handle = strings.TrimSpace(strings.ReplaceAll(d.Handle, "\n", ""))
+	d.Name = strings.TrimSpace(strings.ReplaceAll(d.Name, "\n", ""))
+	d.Repo = strings.TrimSpace(strings.ReplaceAll(d.Repo, "\n", ""))
+	d.RepoDesc = strings.TrimSpace(strings.ReplaceAll(d.RepoDesc, "\n", ""))
+}
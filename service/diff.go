@@ -0,0 +1,39 @@
+package service
+
+// RepoDiff tells how a repository moved since the previous crawl.
+type RepoDiff struct {
+	New bool
+	// RankDelta is positive when the repository climbed up the list
+	RankDelta int
+	StarDelta int
+}
+
+// Diff compares the current crawl with the previous one, the result is aligned
+// with cur. Without a previous crawl nothing can be compared and nil is returned.
+func Diff(prev, cur []*Repo) []RepoDiff {
+	if prev == nil {
+		return nil
+	}
+	type position struct {
+		rank int
+		star int
+	}
+	before := make(map[string]position, len(prev))
+	for i, r := range prev {
+		before[r.Url] = position{rank: i, star: r.StarCount}
+	}
+	diffs := make([]RepoDiff, len(cur))
+	for i, r := range cur {
+		p, ok := before[r.Url]
+		if !ok {
+			diffs[i] = RepoDiff{New: true}
+			continue
+		}
+		diffs[i] = RepoDiff{RankDelta: p.rank - i}
+		// a count of 0 is one the previous crawl could not read, the delta is unknown
+		if p.star > 0 {
+			diffs[i].StarDelta = r.StarCount - p.star
+		}
+	}
+	return diffs
+}
@@ -0,0 +1,36 @@
+package service
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	prev := []*Repo{
+		{Url: "a", StarCount: 1000},
+		{Url: "b", StarCount: 500},
+		{Url: "c", StarCount: 20},
+		{Url: "e"},
+	}
+	cur := []*Repo{
+		{Url: "c", StarCount: 150},
+		{Url: "d", StarCount: 10},
+		{Url: "a", StarCount: 1200},
+		{Url: "e", StarCount: 3000},
+	}
+	if Diff(nil, cur) != nil {
+		t.Error("expected no diff without a previous crawl")
+	}
+	want := []RepoDiff{
+		{RankDelta: 2, StarDelta: 130},
+		{New: true},
+		{RankDelta: -2, StarDelta: 200},
+		{},
+	}
+	got := Diff(prev, cur)
+	if len(got) != len(want) {
+		t.Fatalf("got %d diffs, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("diff %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
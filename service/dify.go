@@ -0,0 +1,128 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	difyEndpoint = "https://api.dify.ai/v1/chat-messages"
+)
+
+type data struct {
+	Event          string                 `json:"event"`
+	ConversationId string                 `json:"conversation_id"`
+	MessageId      string                 `json:"message_id"`
+	CreatedAt      int64                  `json:"created_at"`
+	TaskId         string                 `json:"task_id"`
+	Id             string                 `json:"id"`
+	Position       int                    `json:"position"`
+	Thought        string                 `json:"thought"`
+	Answer         string                 `json:"answer"`
+	Observation    string                 `json:"observation"`
+	Tool           string                 `json:"tool"`
+	ToolLabels     map[string]interface{} `json:"tool_labels"`
+	ToolInput      string                 `json:"tool_input"`
+	MessageFiles   []interface{}          `json:"message_files"`
+}
+
+// difyProvider talks to the Dify chat-messages api and consumes its SSE stream.
+type difyProvider struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	now      func() time.Time
+}
+
+func NewDifyProvider(endpoint, apiKey string) Provider {
+	return newDifyProvider(http.DefaultClient, endpoint, apiKey, time.Now)
+}
+
+func newDifyProvider(client *http.Client, endpoint, apiKey string, now func() time.Time) Provider {
+	return &difyProvider{client: client, endpoint: endpoint, apiKey: apiKey, now: now}
+}
+
+func (p *difyProvider) Name() string {
+	return ProviderDify
+}
+
+func (p *difyProvider) Ask(ctx context.Context, query string) (string, error) {
+	return p.AskStream(ctx, query, nil)
+}
+
+func (p *difyProvider) AskStream(ctx context.Context, query string, partial func(answer string)) (string, error) {
+	requestBody, err := json.Marshal(map[string]interface{}{
+		"inputs":          map[string]interface{}{},
+		"query":           query,
+		"response_mode":   "streaming",
+		"conversation_id": "",
+		"user":            "abc-123",
+		"files": []map[string]string{
+			{
+				"type":            "image",
+				"transfer_method": "remote_url",
+				"url":             "https://cloud.dify.ai/logo/logo-site.png",
+			},
+		},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "json marshal error")
+	}
+
+	// Create a new request
+	req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return "", errors.Wrap(err, "create http request error")
+	}
+
+	// Add headers
+	req.Header.Add("Authorization", "Bearer "+p.apiKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	// Send the request
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "http request error")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", newStatusError(resp, strings.TrimSpace(string(message)), p.now())
+	}
+
+	// Read the response body
+	// Create a new buffered reader to handle the stream
+	reader := bufio.NewReader(resp.Body)
+	var answer string
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		default:
+		}
+		input, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", errors.Wrap(err, "read stream error")
+		}
+		input = strings.TrimPrefix(input, "data: ")
+		var d data
+		if json.Unmarshal([]byte(input), &d) == nil && d.Answer != "" {
+			answer = answer + d.Answer
+			if partial != nil {
+				partial(answer)
+			}
+		}
+		// If the error is EOF, the stream ended normally
+		if err == io.EOF {
+			return answer, nil
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"gitoday/global"
+	htmltemplate "html/template"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DigestMarkdown = "markdown"
+	DigestHTML     = "html"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+type DigestEntry struct {
+	Rank     int
+	Repo     *Repo
+	Analysis *ChatResponse
+	Error    string
+}
+
+// Digest is the data handed to the digest templates.
+type Digest struct {
+	Lang      global.Language
+	Since     global.Since
+	CreatedAt time.Time
+	Entries   []DigestEntry
+}
+
+// Analyze asks AI about every repo with at most concurrency requests in flight,
+// cached analyses are reused. progress is called after each repo finishes.
+func Analyze(ctx context.Context, repos []*Repo, concurrency int, progress func(done, total int)) []DigestEntry {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	entries := make([]DigestEntry, len(repos))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for i, r := range repos {
+		entries[i] = DigestEntry{Rank: i + 1, Repo: r}
+		wg.Add(1)
+		go func(e *DigestEntry) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			e.Analysis, e.Error = analyze(ctx, e.Repo.Url)
+			mu.Lock()
+			done++
+			if progress != nil {
+				progress(done, len(repos))
+			}
+			mu.Unlock()
+		}(&entries[i])
+	}
+	wg.Wait()
+	return entries
+}
+
+func analyze(ctx context.Context, repoUrl string) (*ChatResponse, string) {
+	if cached, ok := LoadAnalysis(repoUrl); ok {
+		return cached, ""
+	}
+	ai, err := Chat(ctx, repoUrl, 3)
+	if err != nil {
+		slog.Error("analyze error", slog.String("repoUrl", repoUrl), slog.String("stack", fmt.Sprintf("%+v", err)))
+		return nil, err.Error()
+	}
+	if err := SaveAnalysis(repoUrl, ai); err != nil {
+		slog.Error("save analysis error", slog.String("repoUrl", repoUrl), slog.String("error", err.Error()))
+	}
+	return ai, ""
+}
+
+// RenderDigest writes the digest in format, templatePath overrides the built-in layout.
+func RenderDigest(w io.Writer, d *Digest, format string, templatePath string) error {
+	switch format {
+	case DigestMarkdown, "":
+		var t *template.Template
+		var err error
+		if templatePath != "" {
+			t, err = template.New(filepath.Base(templatePath)).ParseFiles(templatePath)
+		} else {
+			t, err = template.ParseFS(templateFS, "templates/digest.md.tmpl")
+		}
+		if err != nil {
+			return errors.Wrap(err, "parse template error")
+		}
+		return t.Execute(w, d)
+	case DigestHTML:
+		var t *htmltemplate.Template
+		var err error
+		if templatePath != "" {
+			t, err = htmltemplate.New(filepath.Base(templatePath)).ParseFiles(templatePath)
+		} else {
+			t, err = htmltemplate.ParseFS(templateFS, "templates/digest.html.tmpl")
+		}
+		if err != nil {
+			return errors.Wrap(err, "parse template error")
+		}
+		return t.Execute(w, d)
+	default:
+		return fmt.Errorf("unknown digest format %q", format)
+	}
+}
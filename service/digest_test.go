@@ -0,0 +1,44 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"gitoday/global"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeAndRenderDigest(t *testing.T) {
+	global.SetPreview(true)
+	defer global.SetPreview(false)
+	repos := []*Repo{
+		{Name: "a/one", Url: "https://www.github.com/a/one", Desc: "first <repo>"},
+		{Name: "b/two", Url: "https://www.github.com/b/two"},
+	}
+	calls := 0
+	entries := Analyze(context.Background(), repos, 2, func(done, total int) {
+		calls++
+	})
+	if calls != 2 || len(entries) != 2 || entries[1].Rank != 2 || entries[1].Analysis == nil {
+		t.Fatalf("unexpected entries %+v after %d progress calls", entries, calls)
+	}
+
+	d := &Digest{Lang: global.GoLang, Since: global.Weekly, CreatedAt: time.Now(), Entries: entries}
+	var md bytes.Buffer
+	if err := RenderDigest(&md, d, DigestMarkdown, ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"of this week", "## 1. [a/one](https://www.github.com/a/one)", "**WHY**", "- rclone"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown digest misses %q", want)
+		}
+	}
+	var html bytes.Buffer
+	if err := RenderDigest(&html, d, DigestHTML, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html.String(), "first &lt;repo&gt;") {
+		t.Error("html digest does not escape the description")
+	}
+}
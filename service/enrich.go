@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// enrichConcurrency is how many GitHub API requests run at the same time
+const enrichConcurrency = 4
+
+// RepoMeta is what the GitHub REST API adds to a trending entry.
+type RepoMeta struct {
+	Topics     []string  `json:"topics,omitempty"`
+	License    string    `json:"license,omitempty"`
+	Homepage   string    `json:"homepage,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	PushedAt   time.Time `json:"pushedAt"`
+	OpenIssues int       `json:"openIssues"`
+	Archived   bool      `json:"archived"`
+}
+
+// SetEnrich turns on the GitHub API enrichment after every crawl of the default Client.
+func SetEnrich(enabled bool) {
+	defaultClient.SetEnrich(enabled)
+}
+
+// SetEnrich turns on the GitHub API enrichment after every crawl.
+func (c *Client) SetEnrich(enabled bool) {
+	c.enrichCrawls = enabled
+}
+
+// Enrich fills Meta of the repos from the GitHub API, a repo failing keeps a nil Meta
+// and the first error is returned.
+func Enrich(ctx context.Context, repos []*Repo) error {
+	return defaultClient.Enrich(ctx, repos)
+}
+
+// Enrich fills Meta of the repos from the GitHub API.
+func (c *Client) Enrich(ctx context.Context, repos []*Repo) error {
+	sem := make(chan struct{}, enrichConcurrency)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for _, r := range repos {
+		wg.Add(1)
+		go func(r *Repo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			meta, err := c.FetchRepoMeta(ctx, r.Url)
+			if err != nil {
+				once.Do(func() { firstErr = err })
+				return
+			}
+			r.Meta = meta
+		}(r)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// FetchRepoMeta reads the metadata of a repository url like https://www.github.com/owner/name.
+func FetchRepoMeta(ctx context.Context, repoUrl string) (*RepoMeta, error) {
+	return defaultClient.FetchRepoMeta(ctx, repoUrl)
+}
+
+// FetchRepoMeta reads the metadata of a repository url.
+func (c *Client) FetchRepoMeta(ctx context.Context, repoUrl string) (*RepoMeta, error) {
+	fullName, err := repoFullName(repoUrl)
+	if err != nil {
+		return nil, err
+	}
+	// every crawl asks again, the analysis reuses the answer
+	r, err := c.fetchRepo(ctx, fullName)
+	if err != nil {
+		return nil, err
+	}
+	m := &RepoMeta{
+		Topics:     r.Topics,
+		Homepage:   r.Homepage,
+		CreatedAt:  r.CreatedAt,
+		PushedAt:   r.PushedAt,
+		OpenIssues: r.OpenIssues,
+		Archived:   r.Archived,
+	}
+	if r.License != nil {
+		m.License = r.License.Name
+	}
+	return m, nil
+}
+
+func (c *Client) enrich(repos []*Repo) {
+	if !c.enrichCrawls {
+		return
+	}
+	if err := c.Enrich(context.Background(), repos); err != nil {
+		slog.Error("enrich repos error", slog.String("error", err.Error()))
+	}
+}
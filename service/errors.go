@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// the kinds of failure the UI tells apart, test them with errors.Is
+var (
+	ErrRateLimited = stderrors.New("rate limited")
+	ErrAuth        = stderrors.New("authentication failed")
+	ErrParse       = stderrors.New("answer could not be parsed")
+	ErrTimeout     = stderrors.New("timed out")
+)
+
+// StatusError is a non 200 response of an http api.
+type StatusError struct {
+	StatusCode int
+	// RetryAfter is how long the server asked to wait, zero when it did not say
+	RetryAfter time.Duration
+	Message    string
+}
+
+// newStatusError reads Retry-After, a date is counted from now of the client clock
+func newStatusError(resp *http.Response, message string, now time.Time) *StatusError {
+	e := &StatusError{StatusCode: resp.StatusCode, Message: message}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		e.RetryAfter = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(resp.Header.Get("Retry-After")); err == nil {
+		e.RetryAfter = t.Sub(now)
+	}
+	return e
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("status code is %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("status code is %d", e.StatusCode)
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrAuth:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// temporary is a status worth asking again for
+func (e *StatusError) temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+func (e *SchemaError) Is(target error) bool {
+	return target == ErrParse
+}
+
+// timeoutError keeps the cause of a timeout while matching ErrTimeout
+type timeoutError struct {
+	cause error
+}
+
+func (e *timeoutError) Error() string {
+	return "timed out: " + e.cause.Error()
+}
+
+func (e *timeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+func (e *timeoutError) Unwrap() error {
+	return e.cause
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return stderrors.Is(err, context.DeadlineExceeded) || (stderrors.As(err, &netErr) && netErr.Timeout())
+}
+
+// classify marks timeouts so errors.Is(err, ErrTimeout) holds for them
+func classify(err error) error {
+	if err != nil && isTimeout(err) && !stderrors.Is(err, ErrTimeout) {
+		return &timeoutError{cause: err}
+	}
+	return err
+}
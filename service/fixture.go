@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"gitoday/global"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// httpFixtures keeps the bodies of trending pages and GitHub API responses by url
+	httpFixtures = "http"
+	// aiFixtures keeps the raw AI answers by repository url
+	aiFixtures = "ai"
+	// defaultAnswer is replayed for a repository without a recorded answer
+	defaultAnswer = "default"
+)
+
+//go:embed fixtures
+var builtinFixtures embed.FS
+
+// BuiltinFixtures are the recorded pages and answers preview mode replays.
+func BuiltinFixtures() fs.FS {
+	sub, err := fs.Sub(builtinFixtures, "fixtures")
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
+
+// Record makes the default Client save what it fetches under dir.
+func Record(dir string) {
+	defaultClient.Record(dir)
+}
+
+// Replay makes the default Client answer from fsys instead of the network.
+func Replay(fsys fs.FS) {
+	defaultClient.Replay(fsys)
+}
+
+// Record saves every trending page, GitHub API response and AI answer the client
+// receives under dir, in the layout Replay reads.
+func (c *Client) Record(dir string) {
+	c.recordDir = dir
+}
+
+// Replay answers from fsys instead of the network, a nil fsys goes back online.
+func (c *Client) Replay(fsys fs.FS) {
+	c.replayFS = fsys
+}
+
+// fixtures is what the client replays, preview mode falls back to the built-in fixtures
+func (c *Client) fixtures() fs.FS {
+	if c.replayFS != nil {
+		return c.replayFS
+	}
+	if global.IsPreviewMode() {
+		return BuiltinFixtures()
+	}
+	return nil
+}
+
+func (c *Client) recording() bool {
+	return c.recordDir != "" && c.fixtures() == nil
+}
+
+// do sends req, while replaying it is answered from the fixtures and while
+// recording a successful answer is saved.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if fsys := c.fixtures(); fsys != nil {
+		return replayResponse(fsys, req), nil
+	}
+	if !c.recording() {
+		return c.HTTP.Do(req)
+	}
+	// a 304 answer to a conditional request has no body to record
+	req.Header.Del("If-None-Match")
+	resp, err := c.HTTP.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	c.saveFixture(path.Join(httpFixtures, fixtureName(req.URL.Host+req.URL.Path, req.URL.RawQuery)), body)
+	return resp, nil
+}
+
+func replayResponse(fsys fs.FS, req *http.Request) *http.Response {
+	for _, name := range fixtureCandidates(req.URL) {
+		if b, err := fs.ReadFile(fsys, path.Join(httpFixtures, name)); err == nil {
+			return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Header: http.Header{},
+				Body: io.NopCloser(bytes.NewReader(b)), Request: req}
+		}
+	}
+	return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Header: http.Header{},
+		Body: io.NopCloser(strings.NewReader("no fixture for " + req.URL.String())), Request: req}
+}
+
+// fixtureCandidates is the fixture of u followed by the ones a replay falls back to.
+// A page with a query falls back to the page without it and then to its parents,
+// so one recorded trending page stands in for every language and time window.
+func fixtureCandidates(u *url.URL) []string {
+	names := []string{fixtureName(u.Host+u.Path, u.RawQuery)}
+	if u.RawQuery == "" {
+		return names
+	}
+	for p := strings.TrimSuffix(u.Path, "/"); p != ""; p = p[:strings.LastIndex(p, "/")] {
+		names = append(names, fixtureName(u.Host+p, ""))
+	}
+	return names
+}
+
+// fixtureName is a file name safe on every platform for a url without its scheme
+func fixtureName(hostPath, query string) string {
+	key := strings.Trim(hostPath, "/")
+	if query != "" {
+		key += "?" + query
+	}
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+func answerFixture(repoUrl string) string {
+	u, err := url.Parse(repoUrl)
+	if err != nil {
+		return path.Join(aiFixtures, fixtureName(repoUrl, ""))
+	}
+	return path.Join(aiFixtures, fixtureName(u.Host+u.Path, ""))
+}
+
+// replayChat parses the recorded answer to the repository like a streamed one
+func replayChat(fsys fs.FS, repoUrl string, partial func(answer string)) (*ChatResponse, error) {
+	b, err := fs.ReadFile(fsys, answerFixture(repoUrl))
+	if err != nil {
+		b, err = fs.ReadFile(fsys, path.Join(aiFixtures, defaultAnswer))
+	}
+	if err != nil {
+		err = fmt.Errorf("no recorded answer for %s", repoUrl)
+		return &ChatResponse{Error: err}, err
+	}
+	if partial != nil {
+		partial(string(b))
+	}
+	cr, err := parseAnswer(string(b))
+	if err != nil {
+		return &ChatResponse{Error: err}, err
+	}
+	return cr, nil
+}
+
+// saveFixture is best effort, a failed write only leaves a gap in the recording
+func (c *Client) saveFixture(name string, b []byte) {
+	p := filepath.Join(c.recordDir, filepath.FromSlash(name))
+	err := os.MkdirAll(filepath.Dir(p), 0o755)
+	if err == nil {
+		err = os.WriteFile(p, b, 0o644)
+	}
+	if err != nil {
+		slog.Error("save fixture error", slog.String("fixture", name), slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"gitoday/global"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordReplay(t *testing.T) {
+	page, err := os.ReadFile("fixtures/http/github.com_trending")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/trending/go":
+			w.Write(page)
+		case "/chat/completions":
+			fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"{\"what\":\"a tool\",\"why\":[\"fast\"],\"how\":[\"go\"]}"}}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	p, err := c.NewProvider(ProviderOpenAI, c.GitHubAPI, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetProvider(p)
+	dir := t.TempDir()
+	c.Record(dir)
+	recorded, err := Crawl(global.GoLang, global.Daily, global.AnySpoken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Chat(context.Background(), "https://www.github.com/o/n", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, aiFixtures, "www.github.com_o_n")); err != nil {
+		t.Errorf("answer is not recorded: %v", err)
+	}
+
+	// the replaying client has no server to talk to
+	replay := NewClient(&http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		t.Errorf("unexpected request to %s", r.URL)
+		return nil, fmt.Errorf("offline")
+	})})
+	replay.TrendingURL = c.TrendingURL
+	replay.Replay(os.DirFS(dir))
+	SetClient(replay)
+	repos, err := Crawl(global.GoLang, global.Daily, global.AnySpoken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != len(recorded) || repos[0].Name != recorded[0].Name {
+		t.Errorf("replayed %d repos, recorded %d", len(repos), len(recorded))
+	}
+	cr, err := Chat(context.Background(), "https://www.github.com/o/n", 0)
+	if err != nil || cr.What != "a tool" {
+		t.Errorf("replayed %+v, %v", cr, err)
+	}
+	if _, err := Chat(context.Background(), "https://www.github.com/o/other", 0); err == nil {
+		t.Error("expected a repository without recorded answer to fail")
+	}
+}
+
+func TestPreviewReplaysBuiltinFixtures(t *testing.T) {
+	global.SetPreview(true)
+	defer global.SetPreview(false)
+	// fixtures are embedded, nothing is read from the working directory
+	repos, err := Crawl(global.Rust, global.Weekly, global.Chinese)
+	if err != nil || len(repos) == 0 {
+		t.Fatalf("got %d repos, %v", len(repos), err)
+	}
+	developers, err := CrawlDevelopers(global.GoLang, global.Monthly)
+	if err != nil || len(developers) == 0 {
+		t.Fatalf("got %d developers, %v", len(developers), err)
+	}
+	meta, err := FetchRepoMeta(context.Background(), "https://www.github.com/neovim/neovim")
+	if err != nil || meta.Homepage != "https://neovim.io" {
+		t.Errorf("got meta %+v, %v", meta, err)
+	}
+	answers := map[string]string{}
+	for _, url := range []string{"https://www.github.com/neovim/neovim", "https://www.github.com/astral-sh/uv", "https://www.github.com/o/n"} {
+		cr, err := Chat(context.Background(), url, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", url, err)
+		}
+		answers[cr.What] = url
+	}
+	if len(answers) != 3 {
+		t.Errorf("replayed answers are not per repository: %v", answers)
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type githubRepo struct {
+	Description string   `json:"description"`
+	Homepage    string   `json:"homepage"`
+	Topics      []string `json:"topics"`
+	Language    string   `json:"language"`
+	License     *struct {
+		Name string `json:"name"`
+	} `json:"license"`
+	CreatedAt  time.Time `json:"created_at"`
+	PushedAt   time.Time `json:"pushed_at"`
+	OpenIssues int       `json:"open_issues_count"`
+	Archived   bool      `json:"archived"`
+}
+
+// fetchRepo asks the GitHub API about the repository owner/name and keeps the answer
+func (c *Client) fetchRepo(ctx context.Context, fullName string) (*githubRepo, error) {
+	body, err := c.githubGet(ctx, "/repos/"+fullName, "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+	r := &githubRepo{}
+	if err := json.Unmarshal(body, r); err != nil {
+		return nil, errors.Wrap(err, "json unmarshal error")
+	}
+	c.repos.Lock()
+	defer c.repos.Unlock()
+	if c.repos.byName == nil {
+		c.repos.byName = map[string]*githubRepo{}
+	}
+	c.repos.byName[fullName] = r
+	return r, nil
+}
+
+// repo is the repository fetched before, it is only asked for when there is none
+func (c *Client) repo(ctx context.Context, fullName string) (*githubRepo, error) {
+	c.repos.Lock()
+	r, ok := c.repos.byName[fullName]
+	c.repos.Unlock()
+	if ok {
+		return r, nil
+	}
+	return c.fetchRepo(ctx, fullName)
+}
+
+type githubCacheEntry struct {
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+func DefaultGitHubCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gitoday", "github"), nil
+}
+
+// githubGet requests the GitHub API with $GITHUB_TOKEN if set, it sends the cached ETag
+// and gives up without a request while the rate limit is exhausted.
+func (c *Client) githubGet(ctx context.Context, path, accept string) ([]byte, error) {
+	c.rateLimit.Lock()
+	until := c.rateLimit.until
+	c.rateLimit.Unlock()
+	if c.Now().Before(until) {
+		return nil, fmt.Errorf("github api %w until %s", ErrRateLimited, until.Format(time.Kitchen))
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.GitHubAPI+path, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "create http request error")
+	}
+	req.Header.Add("Accept", accept)
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Add("Authorization", "Bearer "+token)
+	}
+	cachePath := c.githubCachePath(path, accept)
+	cached := loadGitHubCache(cachePath)
+	if cached != nil {
+		req.Header.Add("If-None-Match", cached.ETag)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "http request error")
+	}
+	defer resp.Body.Close()
+	c.updateRateLimit(resp)
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.Body, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return nil, fmt.Errorf("get %s %w", path, ErrRateLimited)
+		}
+		return nil, errors.Wrapf(newStatusError(resp, "", c.Now()), "get %s", path)
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "read body error")
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" && cachePath != "" {
+		saveGitHubCache(cachePath, &githubCacheEntry{ETag: etag, Body: b})
+	}
+	return b, nil
+}
+
+func (c *Client) updateRateLimit(resp *http.Response) {
+	var until time.Time
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			until = time.Unix(reset, 0)
+		}
+	}
+	// the secondary rate limit tells how long to wait instead
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		until = c.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	if until.IsZero() {
+		return
+	}
+	c.rateLimit.Lock()
+	c.rateLimit.until = until
+	c.rateLimit.Unlock()
+}
+
+func (c *Client) githubCachePath(path, accept string) string {
+	if c.githubCacheDir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(accept + "\n" + path))
+	return filepath.Join(c.githubCacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+func loadGitHubCache(path string) *githubCacheEntry {
+	if path == "" {
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var e githubCacheEntry
+	if err := json.Unmarshal(b, &e); err != nil || e.ETag == "" {
+		return nil
+	}
+	return &e
+}
+
+// saveGitHubCache is best effort, a lost entry only costs a full request
+func saveGitHubCache(path string, e *githubCacheEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestEnrichConditionalRequest(t *testing.T) {
+	var full, notModified int
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full++
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"topics":["cli"],"license":{"name":"MIT License"},"open_issues_count":7,"archived":true,"pushed_at":"2024-06-01T00:00:00Z"}`)
+	}))
+	c.InitGitHubCache(t.TempDir())
+
+	for i := 0; i < 2; i++ {
+		repos := []*Repo{{Url: "https://www.github.com/o/n"}}
+		if err := Enrich(context.Background(), repos); err != nil {
+			t.Fatal(err)
+		}
+		m := repos[0].Meta
+		if m == nil || m.License != "MIT License" || m.OpenIssues != 7 || !m.Archived || m.PushedAt.Year() != 2024 {
+			t.Fatalf("unexpected meta %+v", m)
+		}
+	}
+	if full != 1 || notModified != 1 {
+		t.Errorf("got %d full and %d conditional requests, want 1 and 1", full, notModified)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	requests := 0
+	now := time.Now()
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(now.Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	c.Now = func() time.Time { return now }
+
+	repos := []*Repo{{Url: "https://www.github.com/o/a"}}
+	if err := Enrich(context.Background(), repos); err == nil {
+		t.Fatal("expected a rate limited request to fail")
+	}
+	repos = []*Repo{{Url: "https://www.github.com/o/b"}, {Url: "https://www.github.com/o/c"}}
+	if err := Enrich(context.Background(), repos); err == nil {
+		t.Fatal("expected the exhausted rate limit to fail")
+	}
+	if requests != 1 {
+		t.Errorf("sent %d requests, want 1 while the rate limit is exhausted", requests)
+	}
+	// the limit resets an hour later
+	now = now.Add(time.Hour + time.Second)
+	if err := Enrich(context.Background(), repos[:1]); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("error %v is not rate limited", err)
+	}
+	if requests != 2 {
+		t.Errorf("sent %d requests, want 2 after the reset", requests)
+	}
+}
+
+func TestRepoFetchedOnce(t *testing.T) {
+	requests := map[string]int{}
+	newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.URL.Path]++
+		if r.URL.Path == "/repos/o/n" {
+			fmt.Fprint(w, `{"description":"a tool","topics":["cli"],"license":{"name":"MIT License"}}`)
+			return
+		}
+		fmt.Fprint(w, "# n")
+	}))
+	repos := []*Repo{{Url: "https://www.github.com/o/n"}}
+	if err := Enrich(context.Background(), repos); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		rc, err := FetchRepoContext(context.Background(), "https://www.github.com/o/n")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rc.Description != "a tool" || rc.License != "MIT License" || rc.Readme != "# n" {
+			t.Errorf("unexpected context %+v", rc)
+		}
+	}
+	if requests["/repos/o/n"] != 1 {
+		t.Errorf("/repos/o/n requested %d times, want once", requests["/repos/o/n"])
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	openAIEndpoint = "https://api.openai.com/v1"
+	openAIModel    = "gpt-4o-mini"
+)
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// openAIProvider talks to any server implementing the OpenAI chat completions api,
+// the endpoint is the api base url such as http://localhost:11434/v1.
+type openAIProvider struct {
+	client   *http.Client
+	endpoint string
+	apiKey   string
+	model    string
+	now      func() time.Time
+}
+
+func NewOpenAIProvider(endpoint, apiKey, model string) Provider {
+	return newOpenAIProvider(http.DefaultClient, endpoint, apiKey, model, time.Now)
+}
+
+func newOpenAIProvider(client *http.Client, endpoint, apiKey, model string, now func() time.Time) Provider {
+	if model == "" {
+		model = openAIModel
+	}
+	return &openAIProvider{client: client, endpoint: strings.TrimSuffix(endpoint, "/"), apiKey: apiKey, model: model, now: now}
+}
+
+func (p *openAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+func (p *openAIProvider) Ask(ctx context.Context, query string) (string, error) {
+	requestBody, err := json.Marshal(openAIRequest{
+		Model:    p.model,
+		Messages: []openAIMessage{{Role: "user", Content: query}},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "json marshal error")
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", p.endpoint+"/chat/completions", bytes.NewBuffer(requestBody))
+	if err != nil {
+		return "", errors.Wrap(err, "create http request error")
+	}
+	if p.apiKey != "" {
+		req.Header.Add("Authorization", "Bearer "+p.apiKey)
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "http request error")
+	}
+	defer resp.Body.Close()
+
+	var r openAIResponse
+	decodeErr := json.NewDecoder(resp.Body).Decode(&r)
+	if resp.StatusCode != http.StatusOK {
+		var message string
+		if decodeErr == nil && r.Error != nil {
+			message = r.Error.Message
+		}
+		return "", newStatusError(resp, message, p.now())
+	}
+	if decodeErr != nil {
+		return "", errors.Wrap(decodeErr, "json decode error")
+	}
+	if len(r.Choices) == 0 {
+		return "", fmt.Errorf("no choices in response")
+	}
+	return r.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// limits of an answer, a model rambling past them is rather broken than verbose
+const (
+	maxAnswerBytes = 64 * 1024
+	maxWhatRunes   = 1000
+	maxItems       = 10
+	maxItemRunes   = 500
+)
+
+// SchemaError lists every way an answer misses the ChatResponse schema.
+type SchemaError struct {
+	Problems []string
+}
+
+func (e *SchemaError) Error() string {
+	return "invalid answer: " + strings.Join(e.Problems, "; ")
+}
+
+// parseAnswer pulls the first JSON object out of the answer, which may be wrapped
+// in a code fence or prose, and validates it against the ChatResponse schema.
+// A syntax error is repaired leniently once before giving up. Unknown keys are ignored.
+func parseAnswer(answer string) (*ChatResponse, error) {
+	if len(answer) > maxAnswerBytes {
+		return nil, &SchemaError{Problems: []string{fmt.Sprintf("answer is longer than %d bytes", maxAnswerBytes)}}
+	}
+	object, ok := extractObject(answer)
+	if !ok {
+		return nil, &SchemaError{Problems: []string{"no json object in the answer"}}
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(object), &fields); err != nil {
+		if repairErr := json.Unmarshal([]byte(repair(object)), &fields); repairErr != nil {
+			return nil, &SchemaError{Problems: []string{"malformed json: " + err.Error()}}
+		}
+	}
+	return validate(fields)
+}
+
+func validate(fields map[string]json.RawMessage) (*ChatResponse, error) {
+	cr := &ChatResponse{}
+	var problems []string
+	if raw, ok := fields["what"]; !ok {
+		problems = append(problems, "what is missing")
+	} else if err := json.Unmarshal(raw, &cr.What); err != nil {
+		problems = append(problems, "what is not a string")
+	} else if strings.TrimSpace(cr.What) == "" {
+		problems = append(problems, "what is empty")
+	} else if utf8.RuneCountInString(cr.What) > maxWhatRunes {
+		problems = append(problems, fmt.Sprintf("what is longer than %d characters", maxWhatRunes))
+	}
+	for _, list := range []struct {
+		name     string
+		target   *[]string
+		required bool
+	}{
+		{"why", &cr.Why, true},
+		{"how", &cr.How, true},
+		{"other", &cr.Other, false},
+	} {
+		raw, ok := fields[list.name]
+		if !ok {
+			if list.required {
+				problems = append(problems, list.name+" is missing")
+			}
+			continue
+		}
+		if err := json.Unmarshal(raw, list.target); err != nil {
+			problems = append(problems, list.name+" is not an array of strings")
+			continue
+		}
+		items := *list.target
+		if list.required && len(items) == 0 {
+			problems = append(problems, list.name+" is empty")
+		}
+		if len(items) > maxItems {
+			problems = append(problems, fmt.Sprintf("%s has %d items, more than %d", list.name, len(items), maxItems))
+		}
+		for i, item := range items {
+			if utf8.RuneCountInString(item) > maxItemRunes {
+				problems = append(problems, fmt.Sprintf("%s[%d] is longer than %d characters", list.name, i, maxItemRunes))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, &SchemaError{Problems: problems}
+	}
+	return cr, nil
+}
+
+// extractObject returns the first balanced {...} of s, strings are skipped so a
+// brace in a value does not count. An object cut off by the end of s is returned
+// as it is for repair.
+func extractObject(s string) (string, bool) {
+	start := strings.IndexByte(s, '{')
+	if start < 0 {
+		return "", false
+	}
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return s[start : i+1], true
+			}
+		}
+	}
+	return s[start:], true
+}
+
+// repair fixes what models commonly get wrong: // comments copied from the prompt,
+// trailing commas and an answer cut off before its closing quotes and brackets.
+func repair(s string) string {
+	var b strings.Builder
+	var closers []byte
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			b.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '/':
+			if i+1 < len(s) && s[i+1] == '/' {
+				for i < len(s) && s[i] != '\n' {
+					i++
+				}
+				continue
+			}
+		case '{':
+			closers = append(closers, '}')
+		case '[':
+			closers = append(closers, ']')
+		case '}', ']':
+			trimTrailingComma(&b)
+			if len(closers) > 0 {
+				closers = closers[:len(closers)-1]
+			}
+		}
+		b.WriteByte(c)
+	}
+	if inString {
+		b.WriteByte('"')
+	}
+	for i := len(closers) - 1; i >= 0; i-- {
+		trimTrailingComma(&b)
+		b.WriteByte(closers[i])
+	}
+	return b.String()
+}
+
+// trimTrailingComma drops a comma before a closing bracket, with any space after it
+func trimTrailingComma(b *strings.Builder) {
+	s := strings.TrimRight(b.String(), " \t\r\n")
+	if strings.HasSuffix(s, ",") {
+		b.Reset()
+		b.WriteString(strings.TrimSuffix(s, ","))
+	}
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseAnswer(t *testing.T) {
+	cases := []struct {
+		name   string
+		answer string
+	}{
+		{"plain", `{"what":"a tool","why":["fast"],"how":["go"],"other":["b"]}`},
+		{"fenced", "Here you go:\n```json\n{\"what\":\"a tool\",\"why\":[\"fast\"],\"how\":[\"go\"]}\n```\nHope it helps {:"},
+		{"brace in value", `{"what":"a tool","why":["uses {} and \"quotes\""],"how":["go"]} trailing {`},
+		{"comments and trailing commas", "{\n\"what\":\"a tool\",//one line\n\"why\":[\"fast\",],\n\"how\":[\"go\"],\n}"},
+		{"cut off", `{"what":"a tool","why":["fast"],"how":["go","more`},
+	}
+	for _, c := range cases {
+		cr, err := parseAnswer(c.answer)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if cr.What != "a tool" || len(cr.Why) != 1 || len(cr.How) == 0 {
+			t.Errorf("%s: unexpected response %+v", c.name, *cr)
+		}
+	}
+}
+
+func TestParseAnswerInvalid(t *testing.T) {
+	cases := []struct {
+		answer string
+		want   []string
+	}{
+		{`no json here`, []string{"no json object"}},
+		{`{"what":1,"why":"fast"}`, []string{"what is not a string", "why is not an array of strings", "how is missing"}},
+		{`{"what":"a","why":[],"how":["` + strings.Repeat("x", maxItemRunes+1) + `"]}`, []string{"why is empty", "how[0] is longer than"}},
+		{`{"what":"a","why":["a","b","c","d","e","f","g","h","i","j","k"],"how":["go"]}`, []string{"why has 11 items"}},
+	}
+	for _, c := range cases {
+		_, err := parseAnswer(c.answer)
+		var schemaErr *SchemaError
+		if !errors.As(err, &schemaErr) {
+			t.Errorf("%s: expected a schema error, got %v", c.answer, err)
+			continue
+		}
+		for _, want := range c.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: %q does not report %q", c.answer, err, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"gitoday/global"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// readmeTokens is the budget of the README in the prompt
+	readmeTokens = 3000
+	// charsPerToken roughly converts the token budget to characters
+	charsPerToken = 4
+)
+
+// PromptData is what a prompt template can use.
+type PromptData struct {
+	Url         string
+	Name        string
+	Description string
+	Homepage    string
+	Topics      []string
+	// Language is the programming language of the repository
+	Language string
+	License  string
+	// Readme is cut to the token budget
+	Readme string
+	// AnswerLanguage is the english name of the language to answer in
+	AnswerLanguage string
+}
+
+var promptFuncs = template.FuncMap{"join": strings.Join}
+
+// promptTemplate is a parsed prompt, the version is derived from its text so a
+// changed prompt never reads the cached analyses of the old one
+type promptTemplate struct {
+	tmpl    *template.Template
+	version string
+}
+
+// builtinPrompt is the embedded prompt every Client starts with
+var builtinPrompt = func() promptTemplate {
+	b, err := templateFS.ReadFile("templates/prompt.tmpl")
+	if err != nil {
+		panic(err)
+	}
+	p, err := parsePrompt("prompt.tmpl", string(b))
+	if err != nil {
+		panic(err)
+	}
+	return p
+}()
+
+// SetPromptFile replaces the built-in prompt of the default Client with a text/template file,
+// see PromptData for its variables.
+func SetPromptFile(path string) error {
+	return defaultClient.SetPromptFile(path)
+}
+
+// SetPromptFile replaces the built-in prompt with a text/template file.
+func (c *Client) SetPromptFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "read prompt error")
+	}
+	p, err := parsePrompt(path, string(b))
+	if err != nil {
+		return err
+	}
+	c.prompt = p
+	return nil
+}
+
+func parsePrompt(name, text string) (promptTemplate, error) {
+	t, err := template.New(name).Funcs(promptFuncs).Parse(text)
+	if err != nil {
+		return promptTemplate{}, errors.Wrapf(err, "parse prompt %s error", name)
+	}
+	sum := sha256.Sum256([]byte(text))
+	return promptTemplate{tmpl: t, version: hex.EncodeToString(sum[:6])}, nil
+}
+
+func newPromptData(repoUrl string, rc *RepoContext) PromptData {
+	d := PromptData{Url: repoUrl, AnswerLanguage: global.CurrentLocale().Name()}
+	d.Name, _ = repoFullName(repoUrl)
+	if rc != nil {
+		d.Description = rc.Description
+		d.Homepage = rc.Homepage
+		d.Topics = rc.Topics
+		d.Language = rc.Language
+		d.License = rc.License
+		d.Readme = truncate(rc.Readme, readmeTokens*charsPerToken)
+	}
+	return d
+}
+
+func (c *Client) renderPrompt(d PromptData) (string, error) {
+	var b strings.Builder
+	if err := c.prompt.tmpl.Execute(&b, d); err != nil {
+		return "", errors.Wrap(err, "render prompt error")
+	}
+	return b.String(), nil
+}
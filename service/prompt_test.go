@@ -0,0 +1,62 @@
+package service
+
+import (
+	"gitoday/global"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderPrompt(t *testing.T) {
+	defer global.SetLocale(global.LocaleEnglish)
+	c := NewClient(nil)
+	p, err := c.renderPrompt(newPromptData("https://www.github.com/o/n", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(p, "o/n at https://www.github.com/o/n") || !strings.Contains(p, "write every value in English") {
+		t.Errorf("unexpected prompt %s", p)
+	}
+	if strings.Contains(p, "README") {
+		t.Error("rendered the GitHub section without repo context")
+	}
+
+	global.SetLocale(global.LocaleChinese)
+	rc := &RepoContext{Topics: []string{"cli", "go"}, Language: "Go", Readme: strings.Repeat("龙", readmeTokens*charsPerToken+10)}
+	p, err = c.renderPrompt(newPromptData("https://www.github.com/o/n", rc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(p, "write every value in Simplified Chinese") || !strings.Contains(p, "topics: cli, go") || !strings.Contains(p, "language: Go") {
+		t.Errorf("unexpected prompt %s", p[:80])
+	}
+	if n := strings.Count(p, "龙"); n != readmeTokens*charsPerToken {
+		t.Errorf("readme has %d runes, want %d", n, readmeTokens*charsPerToken)
+	}
+}
+
+func TestSetPromptFile(t *testing.T) {
+	c := NewClient(nil)
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	if err := os.WriteFile(path, []byte(`{{.Name}} ({{.Language}}) in {{.AnswerLanguage}}: {{.Description}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetPromptFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if c.prompt.version == builtinPrompt.version {
+		t.Error("a new prompt kept the version of the built-in one")
+	}
+	p, err := c.renderPrompt(newPromptData("https://www.github.com/o/n", &RepoContext{Description: "a tool", Language: "Go"}))
+	if err != nil || p != "o/n (Go) in English: a tool" {
+		t.Errorf("unexpected prompt %q, %v", p, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{{.Missing`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.SetPromptFile(path); err == nil {
+		t.Error("expected a broken template to fail")
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RepoContext is what the GitHub API tells about a repository, it grounds the AI answer.
+type RepoContext struct {
+	Description string
+	Homepage    string
+	Topics      []string
+	// Language is the programming language GitHub detected
+	Language string
+	License  string
+	Readme   string
+}
+
+// FetchRepoContext downloads the metadata and the README of a repository url like
+// https://www.github.com/owner/name, $GITHUB_TOKEN raises the API rate limit.
+func FetchRepoContext(ctx context.Context, repoUrl string) (*RepoContext, error) {
+	return defaultClient.FetchRepoContext(ctx, repoUrl)
+}
+
+// FetchRepoContext downloads the metadata and the README of a repository url.
+func (c *Client) FetchRepoContext(ctx context.Context, repoUrl string) (*RepoContext, error) {
+	fullName, err := repoFullName(repoUrl)
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.repo(ctx, fullName)
+	if err != nil {
+		return nil, err
+	}
+	rc := &RepoContext{Description: r.Description, Homepage: r.Homepage, Topics: r.Topics, Language: r.Language}
+	if r.License != nil {
+		rc.License = r.License.Name
+	}
+	readme, err := c.githubGet(ctx, "/repos/"+fullName+"/readme", "application/vnd.github.raw")
+	if err != nil {
+		// a repository without README still has its metadata
+		return rc, nil
+	}
+	rc.Readme = string(readme)
+	return rc, nil
+}
+
+func repoFullName(repoUrl string) (string, error) {
+	u, err := url.Parse(repoUrl)
+	if err != nil {
+		return "", errors.Wrap(err, "parse repo url error")
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 2 {
+		return "", fmt.Errorf("%s is not a repository url", repoUrl)
+	}
+	return parts[0] + "/" + parts[1], nil
+}
+
+// truncate cuts s to at most n runes
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "\n...(truncated)"
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// stubGitHub serves the GitHub API of o/n and answers 404 for other repositories
+func stubGitHub(t *testing.T, readme string) *Client {
+	return newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/o/n":
+			fmt.Fprint(w, `{"description":"a tool","homepage":"https://n.dev","topics":["cli","go"],"language":"Go","license":{"name":"MIT License"}}`)
+		case "/repos/o/n/readme":
+			if r.Header.Get("Accept") != "application/vnd.github.raw" {
+				t.Errorf("unexpected accept %s", r.Header.Get("Accept"))
+			}
+			fmt.Fprint(w, readme)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestFetchRepoContext(t *testing.T) {
+	stubGitHub(t, "# n\nfast things")
+	c, err := FetchRepoContext(context.Background(), "https://www.github.com/o/n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Description != "a tool" || c.License != "MIT License" || c.Language != "Go" || len(c.Topics) != 2 || c.Readme != "# n\nfast things" {
+		t.Errorf("unexpected context %+v", c)
+	}
+	if _, err := FetchRepoContext(context.Background(), "https://www.github.com/o/missing"); err == nil {
+		t.Error("expected an unknown repository to fail")
+	}
+	if _, err := FetchRepoContext(context.Background(), "https://www.github.com/o"); err == nil {
+		t.Error("expected a url without repository name to fail")
+	}
+}
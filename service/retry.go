@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// retryPolicy retries a call with jittered exponential backoff.
+type retryPolicy struct {
+	attempts int
+	base     time.Duration
+	max      time.Duration
+}
+
+var defaultRetry = retryPolicy{attempts: 3, base: 500 * time.Millisecond, max: 30 * time.Second}
+
+// withAttempts is the policy making n attempts in total
+func (p retryPolicy) withAttempts(n int) retryPolicy {
+	if n < 1 {
+		n = 1
+	}
+	p.attempts = n
+	return p
+}
+
+// withRetry calls fn until it succeeds, fails for good or the attempts of p are used up.
+// Rate limits, 5xx, network errors and parse failures are retried after waiting on
+// the client clock, a Retry-After longer than the max backoff is not waited for.
+func (c *Client) withRetry(ctx context.Context, p retryPolicy, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		if attempt+1 >= p.attempts || !retryable(err) || ctx.Err() != nil {
+			return classify(err)
+		}
+		wait := p.backoff(attempt)
+		var statusErr *StatusError
+		if stderrors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			if statusErr.RetryAfter > p.max {
+				return classify(err)
+			}
+			wait = statusErr.RetryAfter
+		}
+		select {
+		case <-c.After(wait):
+		case <-ctx.Done():
+			return classify(err)
+		}
+	}
+}
+
+// backoff is base*2^attempt capped at max, with the upper half jittered
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	d := p.base << attempt
+	if d > p.max || d <= 0 {
+		d = p.max
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if stderrors.As(err, &statusErr) {
+		return statusErr.temporary()
+	}
+	if stderrors.Is(err, ErrParse) {
+		return true
+	}
+	if stderrors.Is(err, context.Canceled) || stderrors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	return stderrors.As(err, &netErr)
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	cases := []struct {
+		name   string
+		status []int
+		header string
+		calls  int
+		kind   error
+	}{
+		{"recovers from 5xx", []int{503, 502, http.StatusOK}, "", 3, nil},
+		{"gives up after the attempts", []int{500, 500, 500, 500}, "", 3, nil},
+		{"auth is not retried", []int{401}, "", 1, ErrAuth},
+		{"rate limit honours retry-after", []int{429, http.StatusOK}, "0", 2, nil},
+		{"retry-after beyond max is not waited for", []int{429}, "3600", 1, ErrRateLimited},
+	}
+	for _, c := range cases {
+		calls := 0
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			status := c.status[calls]
+			calls++
+			if c.header != "" {
+				w.Header().Set("Retry-After", c.header)
+			}
+			w.WriteHeader(status)
+			fmt.Fprint(w, "<html></html>")
+		}))
+		_, err := client.fetch(client.TrendingURL)
+		if calls != c.calls {
+			t.Errorf("%s: %d calls, want %d", c.name, calls, c.calls)
+		}
+		if c.kind != nil && !errors.Is(err, c.kind) {
+			t.Errorf("%s: error %v is not %v", c.name, err, c.kind)
+		}
+		if c.status[len(c.status)-1] == http.StatusOK && err != nil {
+			t.Errorf("%s: %v", c.name, err)
+		}
+	}
+}
+
+func TestRetryAfterDate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", now.Add(2*time.Hour).Format(http.TimeFormat))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	client.Now = func() time.Time { return now }
+	provider, err := client.NewProvider(ProviderOpenAI, client.GitHubAPI, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, fetchErr := client.fetch(client.TrendingURL)
+	_, askErr := provider.Ask(context.Background(), "q")
+	for _, err := range []error{fetchErr, askErr} {
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.RetryAfter != 2*time.Hour {
+			t.Errorf("error %#v, want a retry after 2h on the client clock", err)
+		}
+	}
+}
+
+func TestChatRetriesParseErrors(t *testing.T) {
+	stubGitHub(t, "")
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		content := `sorry, I can not browse`
+		if calls > 1 {
+			content = `{\"what\":\"a tool\",\"why\":[\"fast\"],\"how\":[\"go\"]}`
+		}
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":"%s"}}]}`, content)
+	}))
+	defer server.Close()
+	Init(NewOpenAIProvider(server.URL, "", ""))
+
+	cr, err := Chat(context.Background(), "https://www.github.com/o/n", 1)
+	if err != nil || cr.What != "a tool" || calls != 2 {
+		t.Errorf("got %+v, %v after %d calls", cr, err, calls)
+	}
+	calls = -10
+	_, err = Chat(context.Background(), "https://www.github.com/o/n", 1)
+	if !errors.Is(err, ErrParse) {
+		t.Errorf("error %v is not a parse error", err)
+	}
+}
+
+func TestChatTimeout(t *testing.T) {
+	stubGitHub(t, "")
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	Init(NewOpenAIProvider(server.URL, "", ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := Chat(ctx, "https://www.github.com/o/n", 2)
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("error %v is not a timeout", err)
+	}
+}
+
+func TestRetryWaitsOnClientClock(t *testing.T) {
+	var waits []time.Duration
+	calls := 0
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "20")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			fmt.Fprint(w, "<html></html>")
+		}
+	}))
+	client.retry = defaultRetry
+	client.After = func(d time.Duration) <-chan time.Time {
+		waits = append(waits, d)
+		ch := make(chan time.Time, 1)
+		ch <- client.Now().Add(d)
+		return ch
+	}
+	if _, err := client.fetch(client.TrendingURL); err != nil {
+		t.Fatal(err)
+	}
+	if len(waits) != 2 || waits[0] < defaultRetry.base/2 || waits[0] > defaultRetry.base || waits[1] != 20*time.Second {
+		t.Errorf("waited %v", waits)
+	}
+}
@@ -0,0 +1,161 @@
+package storage
+
+import (
+	"encoding/json"
+	"gitoday/service"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Bookmark is a repository saved for later together with its AI analysis.
+type Bookmark struct {
+	Name      string                `json:"name"`
+	Url       string                `json:"url"`
+	Desc      string                `json:"desc"`
+	Lang      string                `json:"lang"`
+	Analysis  *service.ChatResponse `json:"analysis,omitempty"`
+	Tags      []string              `json:"tags"`
+	Note      string                `json:"note"`
+	CreatedAt time.Time             `json:"createdAt"`
+}
+
+var (
+	bookmarkPath string
+	bookmarkMu   sync.Mutex
+)
+
+func DefaultBookmarkPath() (string, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "bookmarks.json"), nil
+}
+
+// InitBookmarks sets the json file bookmarks are kept in, it is created on the first save.
+func InitBookmarks(path string) {
+	bookmarkPath = path
+}
+
+// Bookmarks returns the saved repositories, newest first.
+func Bookmarks() ([]Bookmark, error) {
+	bookmarkMu.Lock()
+	defer bookmarkMu.Unlock()
+	return readBookmarks()
+}
+
+func IsBookmarked(url string) bool {
+	bookmarks, err := Bookmarks()
+	if err != nil {
+		return false
+	}
+	for _, b := range bookmarks {
+		if b.Url == url {
+			return true
+		}
+	}
+	return false
+}
+
+// ToggleBookmark saves b, or deletes it if it is already saved, and reports
+// whether it is saved afterwards.
+func ToggleBookmark(b Bookmark) (bool, error) {
+	saved := false
+	err := updateBookmarks(func(bookmarks []Bookmark) []Bookmark {
+		for i, v := range bookmarks {
+			if v.Url == b.Url {
+				return append(bookmarks[:i], bookmarks[i+1:]...)
+			}
+		}
+		saved = true
+		if b.CreatedAt.IsZero() {
+			b.CreatedAt = time.Now()
+		}
+		return append([]Bookmark{b}, bookmarks...)
+	})
+	return saved, err
+}
+
+func DeleteBookmark(url string) error {
+	return updateBookmarks(func(bookmarks []Bookmark) []Bookmark {
+		for i, v := range bookmarks {
+			if v.Url == url {
+				return append(bookmarks[:i], bookmarks[i+1:]...)
+			}
+		}
+		return bookmarks
+	})
+}
+
+// UpdateBookmark replaces the tags and note of a saved repository.
+func UpdateBookmark(url string, tags []string, note string) error {
+	return updateBookmarks(func(bookmarks []Bookmark) []Bookmark {
+		for i := range bookmarks {
+			if bookmarks[i].Url == url {
+				bookmarks[i].Tags = tags
+				bookmarks[i].Note = note
+			}
+		}
+		return bookmarks
+	})
+}
+
+// SetBookmarkAnalysis stores an analysis that finished after the repository was saved.
+func SetBookmarkAnalysis(url string, analysis *service.ChatResponse) error {
+	return updateBookmarks(func(bookmarks []Bookmark) []Bookmark {
+		for i := range bookmarks {
+			if bookmarks[i].Url == url {
+				bookmarks[i].Analysis = analysis
+			}
+		}
+		return bookmarks
+	})
+}
+
+func updateBookmarks(update func([]Bookmark) []Bookmark) error {
+	bookmarkMu.Lock()
+	defer bookmarkMu.Unlock()
+	bookmarks, err := readBookmarks()
+	if err != nil {
+		return err
+	}
+	return writeBookmarks(update(bookmarks))
+}
+
+func readBookmarks() ([]Bookmark, error) {
+	if bookmarkPath == "" {
+		return nil, errors.New("bookmarks are not available")
+	}
+	b, err := os.ReadFile(bookmarkPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "read bookmarks error")
+	}
+	var bookmarks []Bookmark
+	if err := json.Unmarshal(b, &bookmarks); err != nil {
+		return nil, errors.Wrap(err, "json unmarshal error")
+	}
+	return bookmarks, nil
+}
+
+func writeBookmarks(bookmarks []Bookmark) error {
+	if err := os.MkdirAll(filepath.Dir(bookmarkPath), 0o755); err != nil {
+		return errors.Wrap(err, "create bookmark dir error")
+	}
+	b, err := json.MarshalIndent(bookmarks, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "json marshal error")
+	}
+	// keep the old file intact if the write is interrupted
+	tmp := bookmarkPath + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return errors.Wrap(err, "write bookmarks error")
+	}
+	return errors.Wrap(os.Rename(tmp, bookmarkPath), "write bookmarks error")
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"gitoday/service"
+	"path/filepath"
+	"testing"
+)
+
+func TestBookmarks(t *testing.T) {
+	InitBookmarks(filepath.Join(t.TempDir(), "bookmarks.json"))
+	defer InitBookmarks("")
+
+	b := Bookmark{Name: "a/one", Url: "https://www.github.com/a/one", Analysis: &service.ChatResponse{What: "tool"}}
+	saved, err := ToggleBookmark(b)
+	if err != nil || !saved {
+		t.Fatalf("expected bookmark to be saved, got %v %v", saved, err)
+	}
+	if _, err := ToggleBookmark(Bookmark{Name: "b/two", Url: "https://www.github.com/b/two"}); err != nil {
+		t.Fatal(err)
+	}
+	if !IsBookmarked(b.Url) {
+		t.Error("expected a/one to be bookmarked")
+	}
+	if err := UpdateBookmark(b.Url, []string{"cli", "go"}, "try it"); err != nil {
+		t.Fatal(err)
+	}
+	bookmarks, err := Bookmarks()
+	if err != nil || len(bookmarks) != 2 {
+		t.Fatalf("unexpected bookmarks %+v %v", bookmarks, err)
+	}
+	// newest first
+	one := bookmarks[1]
+	if bookmarks[0].Name != "b/two" || len(one.Tags) != 2 || one.Note != "try it" || one.Analysis.What != "tool" {
+		t.Errorf("unexpected bookmarks %+v", bookmarks)
+	}
+
+	saved, err = ToggleBookmark(b)
+	if err != nil || saved {
+		t.Fatalf("expected bookmark to be removed, got %v %v", saved, err)
+	}
+	if err := DeleteBookmark("https://www.github.com/b/two"); err != nil {
+		t.Fatal(err)
+	}
+	if bookmarks, _ := Bookmarks(); len(bookmarks) != 0 {
+		t.Errorf("expected no bookmarks, got %+v", bookmarks)
+	}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"gitoday/global"
+	"gitoday/service"
+	"log/slog"
+)
+
+// the store shared by the TUI and the commands, nil means history is disabled
+var history *Store
+
+func Init(path string) error {
+	s, err := Open(path)
+	if err != nil {
+		return err
+	}
+	history = s
+	return nil
+}
+
+func Close() error {
+	if history == nil {
+		return nil
+	}
+	return history.Close()
+}
+
+// History returns the shared store, nil if Init was not called or failed.
+func History() *Store {
+	return history
+}
+
+// Record saves a crawl result as a new snapshot, preview data is never recorded.
+func Record(lang global.Language, since global.Since, spoken global.SpokenLanguage, repos []*service.Repo) {
+	if history == nil || global.IsPreviewMode() {
+		return
+	}
+	if err := history.Save(NewSnapshot(lang, since, spoken, repos)); err != nil {
+		slog.Error("record snapshot error", slog.String("language", string(lang)), slog.String("error", err.Error()))
+	}
+}
+
+// Previous returns the repositories of the latest recorded crawl, nil if there is none.
+func Previous(lang global.Language, since global.Since, spoken global.SpokenLanguage) []*service.Repo {
+	if history == nil || global.IsPreviewMode() {
+		return nil
+	}
+	snap, err := history.Latest(lang, since, spoken)
+	if err != nil {
+		slog.Error("read previous snapshot error", slog.String("language", string(lang)), slog.String("error", err.Error()))
+		return nil
+	}
+	if snap == nil {
+		return nil
+	}
+	return snap.Repos()
+}
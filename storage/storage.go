@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"gitoday/global"
+	"gitoday/service"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+)
+
+var snapshotsBucket = []byte("snapshots")
+
+// Entry is one repository of a snapshot at the position github ranked it.
+type Entry struct {
+	Position  int    `json:"position"`
+	Name      string `json:"name"`
+	Url       string `json:"url"`
+	Desc      string `json:"desc"`
+	Lang      string `json:"lang"`
+	Star      string `json:"star"`
+	Fork      string `json:"fork"`
+	TodayStar string `json:"todayStar"`
+
+	StarCount      int `json:"starCount"`
+	ForkCount      int `json:"forkCount"`
+	TodayStarCount int `json:"todayStarCount"`
+}
+
+// Snapshot is the trending list of a language and window at one point in time.
+type Snapshot struct {
+	CreatedAt time.Time             `json:"createdAt"`
+	Lang      global.Language       `json:"lang"`
+	Since     global.Since          `json:"since"`
+	Spoken    global.SpokenLanguage `json:"spoken"`
+	Entries   []Entry               `json:"entries"`
+}
+
+// Store keeps snapshots in a bolt database, every language, window and spoken
+// language combination gets its own bucket keyed by the crawl time.
+type Store struct {
+	db *bolt.DB
+}
+
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "gitoday", "history.db"), nil
+}
+
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrap(err, "create storage dir error")
+	}
+	// another gitoday process holding the lock should not block us forever
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, errors.Wrap(err, "open storage error")
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(snapshotsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "create bucket error")
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func seriesKey(lang global.Language, since global.Since, spoken global.SpokenLanguage) []byte {
+	return []byte(fmt.Sprintf("%s|%s|%s", lang, since, spoken))
+}
+
+func timeKey(t time.Time) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(t.UnixNano()))
+	return b
+}
+
+// NewSnapshot turns a crawl result into a snapshot taken now.
+func NewSnapshot(lang global.Language, since global.Since, spoken global.SpokenLanguage, repos []*service.Repo) *Snapshot {
+	entries := make([]Entry, len(repos))
+	for i, r := range repos {
+		entries[i] = Entry{
+			Position:       i + 1,
+			Name:           r.Name,
+			Url:            r.Url,
+			Desc:           r.Desc,
+			Lang:           r.Lang,
+			Star:           r.Star,
+			Fork:           r.Fork,
+			TodayStar:      r.TodayStar,
+			StarCount:      r.StarCount,
+			ForkCount:      r.ForkCount,
+			TodayStarCount: r.TodayStarCount,
+		}
+	}
+	return &Snapshot{
+		CreatedAt: time.Now(),
+		Lang:      lang,
+		Since:     since,
+		Spoken:    spoken,
+		Entries:   entries,
+	}
+}
+
+func (s *Store) Save(snap *Snapshot) error {
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return errors.Wrap(err, "json marshal error")
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		series, err := tx.Bucket(snapshotsBucket).CreateBucketIfNotExists(seriesKey(snap.Lang, snap.Since, snap.Spoken))
+		if err != nil {
+			return err
+		}
+		return series.Put(timeKey(snap.CreatedAt), b)
+	})
+}
+
+// At returns the latest snapshot taken at or before t, nil if there is none.
+func (s *Store) At(lang global.Language, since global.Since, spoken global.SpokenLanguage, t time.Time) (*Snapshot, error) {
+	var snap *Snapshot
+	err := s.db.View(func(tx *bolt.Tx) error {
+		series := tx.Bucket(snapshotsBucket).Bucket(seriesKey(lang, since, spoken))
+		if series == nil {
+			return nil
+		}
+		c := series.Cursor()
+		k, v := c.Seek(timeKey(t.Add(time.Nanosecond)))
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+		if k == nil {
+			return nil
+		}
+		snap = &Snapshot{}
+		return json.Unmarshal(v, snap)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "read snapshot error")
+	}
+	return snap, nil
+}
+
+// Latest returns the most recent snapshot, nil if there is none.
+func (s *Store) Latest(lang global.Language, since global.Since, spoken global.SpokenLanguage) (*Snapshot, error) {
+	return s.At(lang, since, spoken, time.Now())
+}
+
+// Times lists when the snapshots of a series were taken, oldest first.
+func (s *Store) Times(lang global.Language, since global.Since, spoken global.SpokenLanguage) ([]time.Time, error) {
+	var times []time.Time
+	err := s.db.View(func(tx *bolt.Tx) error {
+		series := tx.Bucket(snapshotsBucket).Bucket(seriesKey(lang, since, spoken))
+		if series == nil {
+			return nil
+		}
+		return series.ForEach(func(k, _ []byte) error {
+			times = append(times, time.Unix(0, int64(binary.BigEndian.Uint64(k))))
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "read snapshot error")
+	}
+	return times, nil
+}
+
+// Repos converts the entries back to the crawl result they were made from.
+// Snapshots recorded before the counts were kept get them parsed from the text.
+func (s *Snapshot) Repos() []*service.Repo {
+	repos := make([]*service.Repo, len(s.Entries))
+	for i, e := range s.Entries {
+		repos[i] = &service.Repo{
+			Name:           e.Name,
+			Url:            e.Url,
+			Desc:           e.Desc,
+			Lang:           e.Lang,
+			Star:           e.Star,
+			Fork:           e.Fork,
+			TodayStar:      e.TodayStar,
+			StarCount:      count(e.StarCount, e.Star),
+			ForkCount:      count(e.ForkCount, e.Fork),
+			TodayStarCount: count(e.TodayStarCount, e.TodayStar),
+		}
+	}
+	return repos
+}
+
+// count is n, or the number text starts with when n was not recorded,
+// older crawls kept suffixes like "stars this week" in the text
+func count(n int, text string) int {
+	if fields := strings.Fields(text); n == 0 && len(fields) > 0 {
+		return service.ParseCount(fields[0])
+	}
+	return n
+}
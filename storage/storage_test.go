@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"gitoday/global"
+	"gitoday/service"
+	"path/filepath"
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStoreAt(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	monday := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	for i, name := range []string{"a/monday", "a/tuesday", "a/wednesday"} {
+		snap := NewSnapshot(global.GoLang, global.Daily, global.AnySpoken, []*service.Repo{{Name: name, Star: "1,000"}})
+		snap.CreatedAt = monday.AddDate(0, 0, i)
+		if err := s.Save(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snap, err := s.At(global.GoLang, global.Daily, global.AnySpoken, monday.AddDate(0, 0, 1).Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap == nil || snap.Entries[0].Name != "a/tuesday" || snap.Entries[0].Position != 1 {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+	latest, err := s.Latest(global.GoLang, global.Daily, global.AnySpoken)
+	if err != nil || latest == nil || latest.Entries[0].Name != "a/wednesday" {
+		t.Fatalf("unexpected latest snapshot %+v %v", latest, err)
+	}
+	before, err := s.At(global.GoLang, global.Daily, global.AnySpoken, monday.Add(-time.Hour))
+	if err != nil || before != nil {
+		t.Fatalf("expected no snapshot before the first crawl, got %+v %v", before, err)
+	}
+	other, err := s.Latest(global.Rust, global.Daily, global.AnySpoken)
+	if err != nil || other != nil {
+		t.Fatalf("expected no rust snapshot, got %+v %v", other, err)
+	}
+	times, err := s.Times(global.GoLang, global.Daily, global.AnySpoken)
+	if err != nil || len(times) != 3 || !times[0].Equal(monday) {
+		t.Fatalf("unexpected times %v %v", times, err)
+	}
+}
+
+func TestLegacySnapshotCounts(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	// recorded before the counts were parsed, only the text github rendered is there
+	legacy := `{"createdAt":"2024-06-03T09:00:00Z","lang":"go","since":"weekly","spoken":"","entries":[` +
+		`{"position":1,"name":"o/a","url":"https://www.github.com/o/a","star":"1,200","fork":"1.5k","todayStar":"300 stars this week"}]}`
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		series, err := tx.Bucket(snapshotsBucket).CreateBucketIfNotExists(seriesKey(global.GoLang, global.Weekly, global.AnySpoken))
+		if err != nil {
+			return err
+		}
+		return series.Put(timeKey(time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)), []byte(legacy))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snap, err := s.Latest(global.GoLang, global.Weekly, global.AnySpoken)
+	if err != nil || snap == nil {
+		t.Fatalf("unexpected snapshot %+v %v", snap, err)
+	}
+	prev := snap.Repos()
+	if r := prev[0]; r.StarCount != 1200 || r.ForkCount != 1500 || r.TodayStarCount != 300 {
+		t.Fatalf("counts are not parsed from the text: %+v", r)
+	}
+	diff := service.Diff(prev, []*service.Repo{{Url: "https://www.github.com/o/a", StarCount: 1250}})
+	if diff[0].StarDelta != 50 {
+		t.Errorf("star delta = %d, want 50", diff[0].StarDelta)
+	}
+}
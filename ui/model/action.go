@@ -0,0 +1,93 @@
+package model
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/pkg/errors"
+)
+
+// openBrowser and copyToClipboard are swapped out in tests
+var (
+	openBrowser     = startBrowser
+	copyToClipboard = writeClipboard
+)
+
+// startBrowser opens url with $BROWSER, falling back to the platform opener.
+func startBrowser(url string) error {
+	var cmd *exec.Cmd
+	if browser := os.Getenv("BROWSER"); browser != "" {
+		// $BROWSER may carry arguments, e.g. "firefox --new-tab"
+		args := strings.Fields(browser)
+		cmd = exec.Command(args[0], append(args[1:], url)...)
+	} else {
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", url)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+		default:
+			cmd = exec.Command("xdg-open", url)
+		}
+	}
+	if err := cmd.Start(); err != nil {
+		return errors.Wrap(err, "open browser error")
+	}
+	// reap the process without blocking the UI
+	go cmd.Wait()
+	return nil
+}
+
+// writeClipboard copies text through OSC 52 on terminals known to support it,
+// which also works over ssh, otherwise through the system clipboard.
+func writeClipboard(text string) error {
+	if supportsOSC52() {
+		return writeOSC52(text)
+	}
+	if err := clipboard.WriteAll(text); err != nil {
+		// no clipboard utility installed, the terminal may still understand OSC 52
+		return writeOSC52(text)
+	}
+	return nil
+}
+
+func writeOSC52(text string) error {
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+	// stdout belongs to the bubbletea renderer
+	_, err := seq.WriteTo(os.Stderr)
+	return errors.Wrap(err, "write osc52 error")
+}
+
+func supportsOSC52() bool {
+	if os.Getenv("SSH_TTY") != "" || os.Getenv("TMUX") != "" {
+		return true
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "tmux":
+		return true
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	term := os.Getenv("TERM")
+	for _, t := range []string{"kitty", "alacritty", "foot", "wezterm", "contour"} {
+		if strings.Contains(term, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneCommand is the git clone command of a repository url like https://www.github.com/owner/name
+func cloneCommand(url string) string {
+	return "git clone " + strings.Replace(url, "://www.github.com/", "://github.com/", 1) + ".git"
+}
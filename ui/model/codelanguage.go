@@ -2,17 +2,10 @@ package model
 
 import "gitoday/global"
 
-var codeLanguage = []global.Language{
-	global.All,
-	global.GoLang,
-	global.Java,
-	global.JS,
-	global.TS,
-	global.Python,
-	global.Ruby,
-	global.PHP,
-	global.Swift,
-	global.Kotlin,
-	global.Rust,
-	global.Scala,
+var codeLanguage = global.Languages
+
+var timeWindow = []global.Since{
+	global.Daily,
+	global.Weekly,
+	global.Monthly,
 }
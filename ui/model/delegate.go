@@ -1,9 +1,11 @@
 package model
 
 import (
-	"encoding/json"
 	"fmt"
+	"gitoday/service"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/enescakir/emoji"
@@ -20,16 +22,32 @@ const (
 )
 
 type repoItem struct {
-	Index     int      `json:"index"`
-	Name      string   `json:"name"`
-	Url       string   `json:"url"`
-	Desc      string   `json:"desc"`
-	Lang      string   `json:"lang"`
-	Star      string   `json:"star"`
-	Fork      string   `json:"fork"`
-	TodayStar string   `json:"todayStar"`
-	AIProcess AIStatus `json:"AIProcess"`
-	AIAnswer  string   `json:"AIAnswer"`
+	Index      int      `json:"index"`
+	Name       string   `json:"name"`
+	Url        string   `json:"url"`
+	Desc       string   `json:"desc"`
+	Lang       string   `json:"lang"`
+	Star       string   `json:"star"`
+	Fork       string   `json:"fork"`
+	TodayStar  string   `json:"todayStar"`
+	AIProcess  AIStatus `json:"AIProcess"`
+	AIAnswer   string   `json:"AIAnswer"`
+	AIError    string   `json:"AIError"`
+	AIHint     string   `json:"AIHint"`
+	Bookmarked bool     `json:"bookmarked"`
+	Compared   bool     `json:"compared"`
+	New        bool     `json:"new"`
+	RankDelta  int      `json:"rankDelta"`
+	StarDelta  int      `json:"starDelta"`
+
+	StarCount      int `json:"starCount"`
+	ForkCount      int `json:"forkCount"`
+	TodayStarCount int `json:"todayStarCount"`
+
+	Meta *service.RepoMeta `json:"meta"`
+
+	// search are the terms of the running search, their matches are highlighted
+	search []string
 }
 
 func (r repoItem) String() string {
@@ -37,25 +55,52 @@ func (r repoItem) String() string {
 }
 
 func (r repoItem) Title() string {
-	return fmt.Sprintf("%v %s", emoji.LargeOrangeDiamond, r.Name)
+	icon := emoji.LargeOrangeDiamond
+	if r.New {
+		icon = emoji.NewButton
+	}
+	name := highlight(r.Name, r.search)
+	if r.Bookmarked {
+		return fmt.Sprintf("%v %s %v", icon, name, emoji.Bookmark)
+	}
+	return fmt.Sprintf("%v %s", icon, name)
 }
 
 func (r repoItem) Description() string {
-	lang := fmt.Sprintf("%s%v", r.Lang, emoji.Laptop)
+	lang := fmt.Sprintf("%s%v", highlight(r.Lang, r.search), emoji.Laptop)
 	star := fmt.Sprintf("%s%v", r.Star, emoji.Star)
 	fork := fmt.Sprintf("%s%v", r.Fork, emoji.Wrench)
 	starToday := fmt.Sprintf("%s%v", r.TodayStar, emoji.Fire)
 	s := Trim(r.Desc, getRepoListWidth())
-	des := wrapText(s, uint(getRepoListWidth()))
-	return fmt.Sprintf("  %s  %s  %s  %s", lang, starToday, fork, star) + "\n" + des
+	des := highlight(wrapText(s, uint(getRepoListWidth())), r.search)
+	return fmt.Sprintf("  %s  %s  %s  %s%s", lang, starToday, fork, star, r.movement()) + "\n" + des
 }
 
-func (r repoItem) FilterValue() string {
-	b, err := json.Marshal(r)
-	if err != nil {
+// movement renders the rank move and star delta since the previous crawl
+func (r repoItem) movement() string {
+	if !r.Compared || r.New {
 		return ""
 	}
-	return string(b)
+	var s string
+	if r.StarDelta != 0 {
+		s += fmt.Sprintf(" %+d", r.StarDelta)
+	}
+	switch {
+	case r.RankDelta > 0:
+		s += upStyle.Render(fmt.Sprintf(" ▲%d", r.RankDelta))
+	case r.RankDelta < 0:
+		s += downStyle.Render(fmt.Sprintf(" ▼%d", -r.RankDelta))
+	}
+	return s
+}
+
+// FilterValue is the text the search looks at
+func (r repoItem) FilterValue() string {
+	var texts []string
+	for _, f := range searchFields(r) {
+		texts = append(texts, f.text)
+	}
+	return strings.Join(texts, "\n")
 }
 func newAppItemDelegate() list.DefaultDelegate {
 	d := list.NewDefaultDelegate()
@@ -68,6 +113,14 @@ func newAppItemDelegate() list.DefaultDelegate {
 	}
 	return d
 }
+func newRepoItemDelegate() list.DefaultDelegate {
+	d := newAppItemDelegate()
+	d.ShortHelpFunc = func() []key.Binding {
+		return []key.Binding{localized(repoKeys.Newcomers, msgKeyNewcomers)}
+	}
+	return d
+}
+
 func wrapText(text string, lineWidth uint) string {
 	return wordwrap.WrapString(text, lineWidth)
 }
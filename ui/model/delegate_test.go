@@ -0,0 +1,39 @@
+package model
+
+import (
+	"gitoday/global"
+	"gitoday/service"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// TestFreshListKeys presses the keys reading the selected repo on the list newRepoModel built
+func TestFreshListKeys(t *testing.T) {
+	repos := []*service.Repo{
+		{Name: "o/a", Url: "https://www.github.com/o/a"},
+		{Name: "o/b", Url: "https://www.github.com/o/b"},
+	}
+	diff := []service.RepoDiff{{}, {New: true}}
+	var opened, copied []string
+	openBrowser = func(url string) error { opened = append(opened, url); return nil }
+	copyToClipboard = func(text string) error { copied = append(copied, text); return nil }
+	defer func() { openBrowser, copyToClipboard = startBrowser, writeClipboard }()
+	for _, k := range []string{"n", "s", "S", "o", "c", "y", "b"} {
+		m := newRepoModel(repos, diff, global.Daily)
+		for _, item := range m.repoList.Items() {
+			if _, ok := item.(repoItem); !ok {
+				t.Fatalf("the list holds a %T", item)
+			}
+		}
+		// a panic fails the test
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+	if len(opened) != 1 || opened[0] != repos[0].Url {
+		t.Errorf("opened %v", opened)
+	}
+	if want := []string{repos[0].Url, "git clone https://github.com/o/a.git"}; strings.Join(copied, ",") != strings.Join(want, ",") {
+		t.Errorf("copied %v, want %v", copied, want)
+	}
+}
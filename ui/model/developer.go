@@ -0,0 +1,142 @@
+package model
+
+import (
+	"fmt"
+	"gitoday/global"
+	"gitoday/service"
+	"log/slog"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/enescakir/emoji"
+	"github.com/pkg/errors"
+)
+
+type developerItem struct {
+	Rank     int
+	Handle   string
+	Name     string
+	Url      string
+	Repo     string
+	RepoUrl  string
+	RepoDesc string
+}
+
+func (d developerItem) Title() string {
+	if d.Name == d.Handle {
+		return fmt.Sprintf("%d. %v %s", d.Rank, emoji.Technologist, d.Handle)
+	}
+	return fmt.Sprintf("%d. %v %s (%s)", d.Rank, emoji.Technologist, d.Name, d.Handle)
+}
+
+func (d developerItem) Description() string {
+	if d.Repo == "" {
+		return fmt.Sprintf("  %v %s", emoji.Link, d.Url)
+	}
+	repo := fmt.Sprintf("  %v %s", emoji.Package, d.Repo)
+	des := wrapText(Trim(d.RepoDesc, getDeveloperListWidth()), uint(getDeveloperListWidth()))
+	return repo + "\n" + des
+}
+
+func (d developerItem) FilterValue() string {
+	return d.Handle + " " + d.Name + " " + d.Repo
+}
+
+type developerModel struct {
+	developerList list.Model
+	loaded        bool
+	error         error
+}
+
+func (m developerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *developerModel) updateSize() {
+	m.developerList.SetHeight(getRepoListHeight())
+	m.developerList.SetWidth(getDeveloperListWidth())
+}
+
+func (m developerModel) Update(tmsg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := tmsg.(type) {
+	case tea.WindowSizeMsg:
+		setTerminalSize(msg.Width, msg.Height)
+		m.updateSize()
+		return m, nil
+	case MsgDeveloperCrawlDone:
+		if msg.Error != nil {
+			m.error = msg.Error
+			return m, nil
+		}
+		m.loaded = true
+		return m, m.developerList.SetItems(makeDeveloperItem(msg.Data))
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "tab":
+			return m, EventSwitchView(repoView)
+		case "esc", "q":
+			return m, EventQuitRepoView()
+		}
+		var cmd tea.Cmd
+		m.developerList, cmd = m.developerList.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m developerModel) View() string {
+	if m.error != nil {
+		failed := tr(msgCrawlDevelopersFailed, emoji.TiredFace, m.error.Error())
+		if hint := crawlErrorHint(m.error); hint != "" {
+			failed += "\n" + hint
+		}
+		return repoListStyle.Render(failed)
+	}
+	if !m.loaded {
+		return repoListStyle.Render(tr(msgCrawlingDevelopers, emoji.Crocodile))
+	}
+	return repoListStyle.Render(m.developerList.View())
+}
+
+func newDeveloperModel(since global.Since) developerModel {
+	l := list.New([]list.Item{}, newAppItemDelegate(), getDeveloperListWidth(), getRepoListHeight())
+	l.Title = tr(msgTrendingDevelopers, emoji.Star, sinceLabel(since))
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", tr(msgKeyRepositories))),
+		}
+	}
+	return developerModel{developerList: l}
+}
+
+func makeDeveloperItem(developers []*service.Developer) []list.Item {
+	items := make([]list.Item, len(developers))
+	for i, d := range developers {
+		items[i] = developerItem{
+			Rank:     i + 1,
+			Handle:   d.Handle,
+			Name:     d.Name,
+			Url:      d.Url,
+			Repo:     d.Repo,
+			RepoUrl:  d.RepoUrl,
+			RepoDesc: d.RepoDesc,
+		}
+	}
+	return items
+}
+
+func crawlDevelopers(lang global.Language, since global.Since) tea.Cmd {
+	return func() tea.Msg {
+		slog.Debug("crawl developers start", slog.String("language", string(lang)), slog.String("since", string(since)))
+		res, err := service.CrawlDevelopers(lang, since)
+		if err != nil {
+			slog.Error("crawl developers error", slog.String("language", string(lang)),
+				slog.String("original error:", fmt.Sprintf("%T %v", errors.Cause(err), errors.Cause(err))),
+				slog.String("stack", fmt.Sprintf("%+v", err)))
+			return MsgDeveloperCrawlDone{Error: err}
+		}
+		slog.Debug("crawl developers success", slog.String("language", string(lang)))
+		return MsgDeveloperCrawlDone{Data: res}
+	}
+}
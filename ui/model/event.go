@@ -18,17 +18,34 @@ type MsgQuitLanguageView struct {
 type MsgRestart struct {
 }
 type MsgCrawlDone struct {
-	Data []*service.Repo
+	Data  []*service.Repo
+	Diff  []service.RepoDiff
+	Lang  global.Language
+	Since global.Since
+}
+type MsgDeveloperCrawlDone struct {
+	Data  []*service.Developer
+	Error error
+}
+type MsgSwitchView struct {
+	View StateView
 }
 type MsgTriggerAI struct {
 	Data *repoItem
 }
 type MsgAIFinish struct {
+	Url string
+}
+
+// MsgAIPartial carries the answer of a repo received so far
+type MsgAIPartial struct {
+	Url    string
+	Answer string
 }
 
-func EventAIFinish() tea.Cmd {
+func EventAIFinish(url string) tea.Cmd {
 	return func() tea.Msg {
-		return MsgAIFinish{}
+		return MsgAIFinish{Url: url}
 	}
 }
 
@@ -37,9 +54,15 @@ func EventRestart() tea.Cmd {
 		return MsgRestart{}
 	}
 }
-func EventCrawlDone(data []*service.Repo) tea.Cmd {
+func EventCrawlDone(data []*service.Repo, diff []service.RepoDiff, lang global.Language, since global.Since) tea.Cmd {
+	return func() tea.Msg {
+		return MsgCrawlDone{Data: data, Diff: diff, Lang: lang, Since: since}
+	}
+}
+
+func EventSwitchView(view StateView) tea.Cmd {
 	return func() tea.Msg {
-		return MsgCrawlDone{Data: data}
+		return MsgSwitchView{View: view}
 	}
 }
 
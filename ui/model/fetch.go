@@ -2,7 +2,9 @@ package model
 
 import (
 	"fmt"
+	"gitoday/global"
 	"gitoday/service"
+	"gitoday/storage"
 	"log/slog"
 	"math"
 	"strconv"
@@ -31,16 +33,47 @@ var (
 	progressEmpty = subtleStyle.Render(progressEmptyChar)
 	dotStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("236")).Render(dotChar)
 	mainStyle     = lipgloss.NewStyle().MarginLeft(2)
+	pickerStyle   = lipgloss.NewStyle().Width(24)
 
 	// Gradient colors we'll use for the progress bar
 	ramp = makeRampStyles("#B14FFF", "#00FFA3", progressBarWidth)
 )
 
 func newFetchModel() tea.Model {
-	return fetchModel{0, false, 30, 0, false,
-		0, 0, false, false, nil, make(chan error, 1), make(chan []*service.Repo, 1)}
+	choice, window, spoken := 0, 0, 0
+	for i, v := range codeLanguage {
+		if v == global.DefaultLanguage() {
+			choice = i
+		}
+	}
+	for i, v := range timeWindow {
+		if v == global.DefaultSince() {
+			window = i
+		}
+	}
+	for i, v := range global.SpokenLanguages {
+		if v == global.DefaultSpokenLanguage() {
+			spoken = i
+		}
+	}
+	return fetchModel{
+		choice:       choice,
+		window:       window,
+		spoken:       spoken,
+		ticks:        30,
+		errorChannel: make(chan error, 1),
+		crawlChannel: make(chan crawlResult, 1),
+	}
 }
 
+// picker is the list in the choices view that receives up/down keys
+type picker int
+
+const (
+	languagePicker picker = iota
+	spokenPicker
+)
+
 type (
 	tickMsg  struct{}
 	frameMsg struct{}
@@ -57,22 +90,35 @@ func frame() tea.Cmd {
 		return frameMsg{}
 	})
 }
-func crawl(l int, crawlChannel chan []*service.Repo, errorChannel chan error) {
-	slog.Debug("crawl start", slog.String("language", string(codeLanguage[l])))
-	res, err := service.Crawl(codeLanguage[l])
+
+// crawlResult is the crawled repositories and how they moved since the previous crawl
+type crawlResult struct {
+	repos []*service.Repo
+	diff  []service.RepoDiff
+}
+
+func crawl(l int, w int, s int, crawlChannel chan crawlResult, errorChannel chan error) {
+	slog.Debug("crawl start", slog.String("language", string(codeLanguage[l])), slog.String("since", string(timeWindow[w])),
+		slog.String("spoken", string(global.SpokenLanguages[s])))
+	res, err := service.Crawl(codeLanguage[l], timeWindow[w], global.SpokenLanguages[s])
 	if err != nil {
-		slog.Error("crawl error", slog.String("language", string(codeLanguage[l])),
+		slog.Error("crawl error", slog.String("language", string(codeLanguage[l])), slog.String("since", string(timeWindow[w])),
 			slog.String("original error:", fmt.Sprintf("%T %V", errors.Cause(err), errors.Cause(err))),
 			slog.String("stack", fmt.Sprintf("%+v", err)))
 		errorChannel <- err
 		return
 	}
-	crawlChannel <- res
+	prev := storage.Previous(codeLanguage[l], timeWindow[w], global.SpokenLanguages[s])
+	storage.Record(codeLanguage[l], timeWindow[w], global.SpokenLanguages[s], res)
+	crawlChannel <- crawlResult{repos: res, diff: service.Diff(prev, res)}
 	slog.Debug("crawl success", slog.String("language", string(codeLanguage[l])))
 }
 
 type fetchModel struct {
 	choice       int
+	window       int
+	spoken       int
+	focus        picker
 	chosen       bool
 	ticks        int
 	frames       int
@@ -83,7 +129,7 @@ type fetchModel struct {
 	quitting     bool
 	error        error
 	errorChannel chan error
-	crawlChannel chan []*service.Repo
+	crawlChannel chan crawlResult
 }
 
 func (m fetchModel) Init() tea.Cmd {
@@ -111,7 +157,7 @@ func (m fetchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return updatechoices(msg, m)
 	}
 	if !m.crawling {
-		go crawl(m.choice, m.crawlChannel, m.errorChannel)
+		go crawl(m.choice, m.window, m.spoken, m.crawlChannel, m.errorChannel)
 		m.crawling = true
 	}
 
@@ -139,16 +185,48 @@ func updatechoices(msg tea.Msg, m fetchModel) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
+		case "b":
+			return m, EventSwitchView(savedView)
+		case "tab":
+			if m.focus == languagePicker {
+				m.focus = spokenPicker
+			} else {
+				m.focus = languagePicker
+			}
 		case "j", "down":
+			if m.focus == spokenPicker {
+				m.spoken++
+				if m.spoken > len(global.SpokenLanguages)-1 {
+					m.spoken = len(global.SpokenLanguages) - 1
+				}
+				break
+			}
 			m.choice++
 			if m.choice > len(codeLanguage)-1 {
 				m.choice = len(codeLanguage) - 1
 			}
 		case "k", "up":
+			if m.focus == spokenPicker {
+				m.spoken--
+				if m.spoken < 0 {
+					m.spoken = 0
+				}
+				break
+			}
 			m.choice--
 			if m.choice < 0 {
 				m.choice = 0
 			}
+		case "l", "right":
+			m.window++
+			if m.window > len(timeWindow)-1 {
+				m.window = len(timeWindow) - 1
+			}
+		case "h", "left":
+			m.window--
+			if m.window < 0 {
+				m.window = 0
+			}
 		case "enter":
 			m.chosen = true
 			return m, frame()
@@ -175,7 +253,7 @@ func updatechosen(msg tea.Msg, m fetchModel) (tea.Model, tea.Cmd) {
 			if m.frames > 80 {
 				m.frames = 0
 			}
-			var res []*service.Repo
+			var res crawlResult
 
 			select {
 			case res = <-m.crawlChannel:
@@ -190,8 +268,8 @@ func updatechosen(msg tea.Msg, m fetchModel) (tea.Model, tea.Cmd) {
 			if m.progress >= 1 {
 				m.progress = 1
 				m.loaded = true
-				m.resultCount = len(res)
-				return m, EventCrawlDone(res)
+				m.resultCount = len(res.repos)
+				return m, EventCrawlDone(res.repos, res.diff, codeLanguage[m.choice], timeWindow[m.window])
 			}
 			return m, frame()
 		}
@@ -214,34 +292,58 @@ func updatechosen(msg tea.Msg, m fetchModel) (tea.Model, tea.Cmd) {
 func choicesView(m fetchModel) string {
 	c := m.choice
 
-	tpl := "Which language you want to pick up?\n\n"
+	tpl := tr(msgPickLanguage) + "\n\n"
 	tpl += "%s\n\n"
-	tpl += "Program quits in %s seconds\n\n"
-	tpl += subtleStyle.Render("j/k, up/down: select") + dotStyle +
-		subtleStyle.Render("enter: choose") + dotStyle +
-		subtleStyle.Render("q, esc: quit")
-	var choices string
+	tpl += tr(msgTimeRange) + "\n\n"
+	tpl += tr(msgQuitsIn) + "\n\n"
+	tpl += subtleStyle.Render(tr(msgHelpSelect)) + dotStyle +
+		subtleStyle.Render(tr(msgHelpSwitchPicker)) + dotStyle +
+		subtleStyle.Render(tr(msgHelpTimeRange)) + dotStyle +
+		subtleStyle.Render(tr(msgHelpChoose)) + dotStyle +
+		subtleStyle.Render(tr(msgHelpSaved)) + dotStyle +
+		subtleStyle.Render(tr(msgHelpQuit))
+	choices := pickerTitle(tr(msgPickerCode), m.focus == languagePicker) + "\n"
 	for i, v := range codeLanguage {
 		choices += checkbox(string(v), i == c) + "\n"
 	}
+	spoken := pickerTitle(tr(msgPickerSpoken), m.focus == spokenPicker) + "\n"
+	for i, v := range global.SpokenLanguages {
+		spoken += checkbox(v.Label(), i == m.spoken) + "\n"
+	}
+	choices = lipgloss.JoinHorizontal(lipgloss.Top, pickerStyle.Render(choices), pickerStyle.Render(spoken))
 
-	return fmt.Sprintf(tpl, choices, ticksStyle.Render(strconv.Itoa(m.ticks)))
+	var windows []string
+	for i, v := range timeWindow {
+		windows = append(windows, checkbox(sinceName(v), i == m.window))
+	}
+
+	return fmt.Sprintf(tpl, choices, strings.Join(windows, "  "), ticksStyle.Render(strconv.Itoa(m.ticks)))
 }
 
 // The second view, after a task has been chosen
 func chosenView(m fetchModel) string {
 	var msg string
-	label := fmt.Sprintf("%v Crawling most excited %s porject about today in github", emoji.Crocodile, codeLanguage[m.choice])
+	label := tr(msgCrawling, emoji.Crocodile, codeLanguage[m.choice], sinceLabel(timeWindow[m.window]))
 	if m.loaded {
-		label = fmt.Sprintf("Prefetch %d %s projects success,waiting for navigate or press [ENTER]", m.resultCount, codeLanguage[m.choice])
+		label = tr(msgPrefetched, m.resultCount, codeLanguage[m.choice], sinceLabel(timeWindow[m.window]))
 	}
 	if m.error != nil {
-		label = fmt.Sprintf("Error: %s. \nExiting in %s seconds...", m.error.Error(), ticksStyle.Render(strconv.Itoa(m.ticks)))
+		label = tr(msgFetchError, m.error.Error(), ticksStyle.Render(strconv.Itoa(m.ticks)))
+		if hint := crawlErrorHint(m.error); hint != "" {
+			label += "\n" + hint
+		}
 	}
 
 	return msg + "\n\n" + label + "\n" + progressbar(m.progress) + "%"
 }
 
+func pickerTitle(title string, focused bool) string {
+	if focused {
+		return keywordStyle.Render("> " + title)
+	}
+	return subtleStyle.Render("  " + title)
+}
+
 func progressbar(percent float64) string {
 	w := float64(progressBarWidth)
 
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"errors"
+	"gitoday/service"
+)
+
+// aiErrorHint tells the user what to do about a failed analysis
+func aiErrorHint(err error) string {
+	switch {
+	case errors.Is(err, service.ErrRateLimited):
+		return tr(msgHintAIRateLimited)
+	case errors.Is(err, service.ErrAuth):
+		return tr(msgHintAIAuth)
+	case errors.Is(err, service.ErrParse):
+		return tr(msgHintAIParse)
+	case errors.Is(err, service.ErrTimeout):
+		return tr(msgHintAITimeout)
+	}
+	return ""
+}
+
+// crawlErrorHint tells the user what to do about a failed crawl
+func crawlErrorHint(err error) string {
+	switch {
+	case errors.Is(err, service.ErrRateLimited):
+		return tr(msgHintCrawlRateLimited)
+	case errors.Is(err, service.ErrTimeout):
+		return tr(msgHintCrawlTimeout)
+	}
+	return ""
+}
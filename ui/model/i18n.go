@@ -0,0 +1,328 @@
+package model
+
+import (
+	"fmt"
+	"gitoday/global"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// msgID names a string of the TUI, its text lives in the catalog of every locale
+type msgID int
+
+const (
+	msgToday msgID = iota
+	msgThisWeek
+	msgThisMonth
+	msgDaily
+	msgWeekly
+	msgMonthly
+
+	// fetch view
+	msgPickLanguage
+	msgTimeRange
+	msgQuitsIn
+	msgHelpSelect
+	msgHelpSwitchPicker
+	msgHelpTimeRange
+	msgHelpChoose
+	msgHelpSaved
+	msgHelpQuit
+	msgPickerCode
+	msgPickerSpoken
+	msgCrawling
+	msgPrefetched
+	msgFetchError
+
+	// repo view
+	msgTrending
+	msgAnalysing
+	msgOpened
+	msgCopied
+	msgBookmarked
+	msgUnbookmarked
+	msgNothingToAnalyse
+	msgAnalysed
+	msgSearchPlaceholder
+	msgSearchCount
+	msgNoMatches
+	msgNewcomers
+	msgNewcomerTitle
+	msgDetailTitle
+	msgAIInProgress
+	msgAIFailed
+	msgAISuccess
+	msgAIReady
+	msgWhy
+	msgHow
+	msgMore
+	msgArchived
+	msgActivity
+	msgSortRank
+	msgSortTodayStar
+	msgSortStar
+	msgSortFork
+	msgSortName
+
+	// developer view
+	msgTrendingDevelopers
+	msgCrawlingDevelopers
+	msgCrawlDevelopersFailed
+
+	// saved view
+	msgSavedTitle
+	msgNothingSaved
+	msgLoadBookmarksFailed
+	msgSavedAt
+	msgDeleted
+	msgNotePlaceholder
+	msgTagsPlaceholder
+
+	// key help
+	msgKeyAnalyze
+	msgKeyAnalyzeAll
+	msgKeyRefresh
+	msgKeyDevelopers
+	msgKeyNewcomers
+	msgKeyBookmark
+	msgKeyOpen
+	msgKeyCopyUrl
+	msgKeyCopyClone
+	msgKeySort
+	msgKeyReverse
+	msgKeySearch
+	msgKeyBack
+	msgKeyTags
+	msgKeyNote
+	msgKeyDelete
+	msgKeyRepositories
+
+	// error guidance
+	msgHintAIRateLimited
+	msgHintAIAuth
+	msgHintAIParse
+	msgHintAITimeout
+	msgHintCrawlRateLimited
+	msgHintCrawlTimeout
+)
+
+var catalog = map[global.Locale]map[msgID]string{
+	global.LocaleEnglish: {
+		msgToday:     "today",
+		msgThisWeek:  "this week",
+		msgThisMonth: "this month",
+		msgDaily:     "daily",
+		msgWeekly:    "weekly",
+		msgMonthly:   "monthly",
+
+		msgPickLanguage:     "Which language you want to pick up?",
+		msgTimeRange:        "Time range: %s",
+		msgQuitsIn:          "Program quits in %s seconds",
+		msgHelpSelect:       "j/k, up/down: select",
+		msgHelpSwitchPicker: "tab: switch picker",
+		msgHelpTimeRange:    "h/l, left/right: time range",
+		msgHelpChoose:       "enter: choose",
+		msgHelpSaved:        "b: saved",
+		msgHelpQuit:         "q, esc: quit",
+		msgPickerCode:       "Code",
+		msgPickerSpoken:     "Spoken",
+		msgCrawling:         "%v Crawling most excited %s porject about %s in github",
+		msgPrefetched:       "Prefetch %d %s projects of %s success,waiting for navigate or press [ENTER]",
+		msgFetchError:       "Error: %s. \nExiting in %s seconds...",
+
+		msgTrending:          "%v Top Repositories of %s %[1]v",
+		msgAnalysing:         "%v Analysing %d/%d  %s",
+		msgOpened:            "Opened %s",
+		msgCopied:            "Copied %s",
+		msgBookmarked:        "Saved %s",
+		msgUnbookmarked:      "Removed %s from saved",
+		msgNothingToAnalyse:  "Nothing left to analyse",
+		msgAnalysed:          "Analysed %d repositories",
+		msgSearchPlaceholder: "name, description, language or AI answer",
+		msgSearchCount:       "%d of %d",
+		msgNoMatches:         "%v Nothing matches %q, press [ESC] to see every repository",
+		msgNewcomers:         "%d new since the previous crawl",
+		msgNewcomerTitle:     "new",
+		msgDetailTitle:       "%v Repository Inspiration %v",
+		msgAIInProgress:      "%v AI is analyzing the project,please waiting...%v",
+		msgAIFailed:          "%v AI is tired,please press [ENTER] to retry later.",
+		msgAISuccess:         "%v AI analyse finished, press [R] to refresh %v\n\n%s",
+		msgAIReady:           "%v Press [ENTER] to unlock AI Power %v",
+		msgWhy:               "WHY",
+		msgHow:               "HOW",
+		msgMore:              "MORE",
+		msgArchived:          "%v archived",
+		msgActivity:          "%v created %s, pushed %s, %d open issues",
+		msgSortRank:          "rank",
+		msgSortTodayStar:     "today stars",
+		msgSortStar:          "stars",
+		msgSortFork:          "forks",
+		msgSortName:          "name",
+
+		msgTrendingDevelopers:    "%v Top Developers of %s %[1]v",
+		msgCrawlingDevelopers:    "%v Crawling trending developers...",
+		msgCrawlDevelopersFailed: "%v Crawl developers failed: %s\n\npress [TAB] to go back to repositories",
+
+		msgSavedTitle:          "%v Saved Repositories %v",
+		msgNothingSaved:        "%v Nothing saved yet, press [B] in the repository view to save one.",
+		msgLoadBookmarksFailed: "%v Load bookmarks failed: %s",
+		msgSavedAt:             "%v saved at %s",
+		msgDeleted:             "Deleted %s",
+		msgNotePlaceholder:     "a note about this repository",
+		msgTagsPlaceholder:     "comma separated tags",
+
+		msgKeyAnalyze:      "analyse",
+		msgKeyAnalyzeAll:   "analyse all",
+		msgKeyRefresh:      "refresh AI",
+		msgKeyDevelopers:   "developers",
+		msgKeyNewcomers:    "newcomers",
+		msgKeyBookmark:     "save",
+		msgKeyOpen:         "open",
+		msgKeyCopyUrl:      "copy url",
+		msgKeyCopyClone:    "copy git clone",
+		msgKeySort:         "sort",
+		msgKeyReverse:      "reverse",
+		msgKeySearch:       "search",
+		msgKeyBack:         "back",
+		msgKeyTags:         "tags",
+		msgKeyNote:         "note",
+		msgKeyDelete:       "delete",
+		msgKeyRepositories: "repositories",
+
+		msgHintAIRateLimited:    "The AI provider is rate limiting, wait a moment then press [ENTER] to retry.",
+		msgHintAIAuth:           "The AI provider rejected the key, check API_KEY or ai.api_key in the config file.",
+		msgHintAIParse:          "The model did not answer in the expected format, press [ENTER] to ask again or try another model or prompt.",
+		msgHintAITimeout:        "The AI took too long, press [ENTER] to retry or raise ai.timeout in the config file.",
+		msgHintCrawlRateLimited: "GitHub is rate limiting, try again in a few minutes.",
+		msgHintCrawlTimeout:     "GitHub did not answer in time, check the network or raise crawl_timeout in the config file.",
+	},
+	global.LocaleChinese: {
+		msgToday:     "今日",
+		msgThisWeek:  "本周",
+		msgThisMonth: "本月",
+		msgDaily:     "每日",
+		msgWeekly:    "每周",
+		msgMonthly:   "每月",
+
+		msgPickLanguage:     "想看哪种语言的项目？",
+		msgTimeRange:        "时间范围：%s",
+		msgQuitsIn:          "程序将在 %s 秒后退出",
+		msgHelpSelect:       "j/k, 上/下：选择",
+		msgHelpSwitchPicker: "tab：切换选择器",
+		msgHelpTimeRange:    "h/l, 左/右：时间范围",
+		msgHelpChoose:       "enter：确定",
+		msgHelpSaved:        "b：收藏",
+		msgHelpQuit:         "q, esc：退出",
+		msgPickerCode:       "编程语言",
+		msgPickerSpoken:     "自然语言",
+		msgCrawling:         "%v 正在抓取 GitHub 上%[3]s最热门的 %[2]s 项目",
+		msgPrefetched:       "已抓取%[3]s %[1]d 个 %[2]s 项目，按 [ENTER] 查看",
+		msgFetchError:       "出错了：%s。\n%s 秒后退出...",
+
+		msgTrending:          "%v %s热门项目 %[1]v",
+		msgAnalysing:         "%v 正在分析 %d/%d  %s",
+		msgOpened:            "已打开 %s",
+		msgCopied:            "已复制 %s",
+		msgBookmarked:        "已收藏 %s",
+		msgUnbookmarked:      "已取消收藏 %s",
+		msgNothingToAnalyse:  "没有需要分析的项目",
+		msgAnalysed:          "已分析 %d 个项目",
+		msgSearchPlaceholder: "名称、描述、语言或 AI 分析",
+		msgSearchCount:       "%d / %d",
+		msgNoMatches:         "%v 没有项目匹配 %q，按 [ESC] 查看全部项目",
+		msgNewcomers:         "比上次抓取新上榜 %d 个",
+		msgNewcomerTitle:     "新上榜",
+		msgDetailTitle:       "%v 项目灵感 %v",
+		msgAIInProgress:      "%v AI 正在分析这个项目，请稍候...%v",
+		msgAIFailed:          "%v AI 累了，请稍后按 [ENTER] 重试。",
+		msgAISuccess:         "%v AI 分析完成，按 [R] 刷新 %v\n\n%s",
+		msgAIReady:           "%v 按 [ENTER] 解锁 AI 分析 %v",
+		msgWhy:               "为什么",
+		msgHow:               "怎么做",
+		msgMore:              "相似项目",
+		msgArchived:          "%v 已归档",
+		msgActivity:          "%v 创建于 %s，最近推送 %s，%d 个未关闭 issue",
+		msgSortRank:          "排名",
+		msgSortTodayStar:     "今日星标",
+		msgSortStar:          "星标",
+		msgSortFork:          "fork",
+		msgSortName:          "名称",
+
+		msgTrendingDevelopers:    "%v %s热门开发者 %[1]v",
+		msgCrawlingDevelopers:    "%v 正在抓取热门开发者...",
+		msgCrawlDevelopersFailed: "%v 抓取开发者失败：%s\n\n按 [TAB] 返回项目列表",
+
+		msgSavedTitle:          "%v 收藏的项目 %v",
+		msgNothingSaved:        "%v 还没有收藏，在项目列表中按 [B] 收藏。",
+		msgLoadBookmarksFailed: "%v 读取收藏失败：%s",
+		msgSavedAt:             "%v 收藏于 %s",
+		msgDeleted:             "已删除 %s",
+		msgNotePlaceholder:     "写点关于这个项目的备注",
+		msgTagsPlaceholder:     "逗号分隔的标签",
+
+		msgKeyAnalyze:      "分析",
+		msgKeyAnalyzeAll:   "全部分析",
+		msgKeyRefresh:      "重新分析",
+		msgKeyDevelopers:   "开发者",
+		msgKeyNewcomers:    "新上榜",
+		msgKeyBookmark:     "收藏",
+		msgKeyOpen:         "打开",
+		msgKeyCopyUrl:      "复制链接",
+		msgKeyCopyClone:    "复制 git clone",
+		msgKeySort:         "排序",
+		msgKeyReverse:      "反序",
+		msgKeySearch:       "搜索",
+		msgKeyBack:         "返回",
+		msgKeyTags:         "标签",
+		msgKeyNote:         "备注",
+		msgKeyDelete:       "删除",
+		msgKeyRepositories: "项目",
+
+		msgHintAIRateLimited:    "AI 服务限流了，稍等片刻再按 [ENTER] 重试。",
+		msgHintAIAuth:           "AI 服务拒绝了密钥，请检查 API_KEY 或配置文件中的 ai.api_key。",
+		msgHintAIParse:          "模型的回答格式不对，按 [ENTER] 重新提问，或换一个模型或提示词。",
+		msgHintAITimeout:        "AI 响应超时，按 [ENTER] 重试，或调大配置文件中的 ai.timeout。",
+		msgHintCrawlRateLimited: "GitHub 限流了，请几分钟后再试。",
+		msgHintCrawlTimeout:     "GitHub 响应超时，请检查网络或调大配置文件中的 crawl_timeout。",
+	},
+}
+
+// tr renders the string of the current locale, falling back to English
+func tr(id msgID, args ...interface{}) string {
+	s, ok := catalog[global.CurrentLocale()][id]
+	if !ok {
+		s = catalog[global.LocaleEnglish][id]
+	}
+	if len(args) == 0 {
+		return s
+	}
+	return fmt.Sprintf(s, args...)
+}
+
+// localized returns the binding with its help text in the current locale
+func localized(b key.Binding, id msgID) key.Binding {
+	b.SetHelp(b.Help().Key, tr(id))
+	return b
+}
+
+func sinceLabel(s global.Since) string {
+	switch s {
+	case global.Weekly:
+		return tr(msgThisWeek)
+	case global.Monthly:
+		return tr(msgThisMonth)
+	default:
+		return tr(msgToday)
+	}
+}
+
+func sinceName(s global.Since) string {
+	switch s {
+	case global.Weekly:
+		return tr(msgWeekly)
+	case global.Monthly:
+		return tr(msgMonthly)
+	default:
+		return tr(msgDaily)
+	}
+}
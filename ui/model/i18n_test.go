@@ -0,0 +1,30 @@
+package model
+
+import (
+	"gitoday/global"
+	"testing"
+)
+
+func TestCatalogComplete(t *testing.T) {
+	for _, l := range global.Locales {
+		for id := range catalog[global.LocaleEnglish] {
+			if catalog[l][id] == "" {
+				t.Errorf("locale %s misses message %d", l, id)
+			}
+		}
+	}
+}
+
+func TestTr(t *testing.T) {
+	defer global.SetLocale(global.LocaleEnglish)
+	if got := tr(msgAnalysed, 3); got != "Analysed 3 repositories" {
+		t.Errorf("got %q", got)
+	}
+	global.SetLocale(global.LocaleChinese)
+	if got := tr(msgTrending, "*", sinceLabel(global.Weekly)); got != "* 本周热门项目 *" {
+		t.Errorf("got %q", got)
+	}
+	if got := tr(msgPrefetched, 25, "go", sinceLabel(global.Daily)); got != "已抓取今日 25 个 go 项目，按 [ENTER] 查看" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// repoKeyMap are the actions of the repo view, the keys can be changed in the config file
+type repoKeyMap struct {
+	Analyze    key.Binding
+	AnalyzeAll key.Binding
+	Refresh    key.Binding
+	Developers key.Binding
+	Newcomers  key.Binding
+	Bookmark   key.Binding
+	Open       key.Binding
+	CopyUrl    key.Binding
+	CopyClone  key.Binding
+	Sort       key.Binding
+	Reverse    key.Binding
+	Search     key.Binding
+	Quit       key.Binding
+}
+
+var repoKeys = defaultRepoKeyMap()
+
+func defaultRepoKeyMap() repoKeyMap {
+	return repoKeyMap{
+		Analyze:    key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "analyse")),
+		AnalyzeAll: key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "analyse all")),
+		Refresh:    key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "refresh AI")),
+		Developers: key.NewBinding(key.WithKeys("tab"), key.WithHelp("tab", "developers")),
+		Newcomers:  key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "newcomers")),
+		Bookmark:   key.NewBinding(key.WithKeys("b"), key.WithHelp("b", "save")),
+		Open:       key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open")),
+		CopyUrl:    key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "copy url")),
+		CopyClone:  key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy git clone")),
+		Sort:       key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "sort")),
+		Reverse:    key.NewBinding(key.WithKeys("S"), key.WithHelp("S", "reverse")),
+		Search:     key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		Quit:       key.NewBinding(key.WithKeys("q", "esc"), key.WithHelp("q", "back")),
+	}
+}
+
+// actions maps the action names used in the config file to their bindings
+func (k *repoKeyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"analyze":     &k.Analyze,
+		"analyze_all": &k.AnalyzeAll,
+		"refresh":     &k.Refresh,
+		"developers":  &k.Developers,
+		"newcomers":   &k.Newcomers,
+		"bookmark":    &k.Bookmark,
+		"open":        &k.Open,
+		"copy_url":    &k.CopyUrl,
+		"copy_clone":  &k.CopyClone,
+		"sort":        &k.Sort,
+		"reverse":     &k.Reverse,
+		"search":      &k.Search,
+		"quit":        &k.Quit,
+	}
+}
+
+// shortHelp lists the bindings shown under the repo list, newcomers is shown by the delegate
+func (k repoKeyMap) shortHelp() []key.Binding {
+	return []key.Binding{
+		localized(k.Search, msgKeySearch),
+		localized(k.Developers, msgKeyDevelopers),
+		localized(k.AnalyzeAll, msgKeyAnalyzeAll),
+		localized(k.Refresh, msgKeyRefresh),
+		localized(k.Bookmark, msgKeyBookmark),
+		localized(k.Open, msgKeyOpen),
+		localized(k.CopyUrl, msgKeyCopyUrl),
+		localized(k.CopyClone, msgKeyCopyClone),
+		localized(k.Sort, msgKeySort),
+		localized(k.Reverse, msgKeyReverse),
+	}
+}
+
+// SetKeyBindings replaces the keys of the repo view actions.
+func SetKeyBindings(bindings map[string][]string) error {
+	actions := repoKeys.actions()
+	for action, keys := range bindings {
+		b, ok := actions[action]
+		if !ok {
+			return fmt.Errorf("unknown key binding action %q", action)
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		help := strings.Join(keys, "/")
+		b.SetKeys(keys...)
+		b.SetHelp(help, b.Help().Desc)
+	}
+	return nil
+}
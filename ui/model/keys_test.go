@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSetKeyBindings(t *testing.T) {
+	defer func() { repoKeys = defaultRepoKeyMap() }()
+	if err := SetKeyBindings(map[string][]string{"quit": {"x"}, "open": {"o", "ctrl+o"}}); err != nil {
+		t.Fatal(err)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")}, repoKeys.Quit) {
+		t.Error("x does not quit")
+	}
+	if key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}, repoKeys.Quit) {
+		t.Error("q still quits after rebinding")
+	}
+	if h := repoKeys.Open.Help().Key; h != "o/ctrl+o" {
+		t.Errorf("open help = %q", h)
+	}
+	if err := SetKeyBindings(map[string][]string{"fly": {"f"}}); err == nil {
+		t.Error("expected an unknown action to fail")
+	}
+}
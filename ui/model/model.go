@@ -3,6 +3,7 @@ package model
 import (
 	"context"
 	"fmt"
+	"gitoday/global"
 	"gitoday/service"
 	"log/slog"
 
@@ -15,13 +16,19 @@ type StateView uint
 const (
 	fetchView StateView = iota + 1
 	repoView
+	developerView
+	savedView
 )
 
 type MainModel struct {
-	activeView    StateView
-	languageModel tea.Model
-	repoModel     tea.Model
-	fetchView     tea.Model
+	activeView     StateView
+	languageModel  tea.Model
+	repoModel      tea.Model
+	developerModel tea.Model
+	savedModel     tea.Model
+	fetchView      tea.Model
+	lang           global.Language
+	since          global.Since
 }
 
 // implement the mdoel interface
@@ -31,16 +38,75 @@ func (m MainModel) Init() tea.Cmd {
 
 // implement the mdoel interface
 func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// ctrl+c leaves the program from every tab, the fetch view closes its crawl itself
+	if k, ok := msg.(tea.KeyMsg); ok && k.String() == "ctrl+c" && m.activeView != fetchView {
+		m.tearDown()
+		return m, tea.Quit
+	}
 	switch msg := msg.(type) {
 	case MsgRestart:
 		m := NewModel()
 		return m, m.Init()
+	case MsgQuitRepoView:
+		// the repo and developers tabs both go back to the chooser
+		m.tearDown()
+		return m, EventRestart()
 	case MsgCrawlDone:
 		m.activeView = repoView
-		m.repoModel = newRepoModel(msg.Data)
+		m.lang = msg.Lang
+		m.since = msg.Since
+		m.repoModel = newRepoModel(msg.Data, msg.Diff, msg.Since)
 		return m, m.repoModel.Init()
+	case MsgSwitchView:
+		m.activeView = msg.View
+		if msg.View == savedView {
+			// bookmarks may have changed since the view was last opened
+			m.savedModel = newSavedModel()
+			return m, nil
+		}
+		// a failed crawl is tried again the next time the tab is opened
+		if d, ok := m.developerModel.(developerModel); msg.View == developerView && (m.developerModel == nil || ok && d.error != nil) {
+			m.developerModel = newDeveloperModel(m.since)
+			return m, crawlDevelopers(m.lang, m.since)
+		}
+		return m, nil
+	case MsgAIFinish, MsgAIPartial:
+		// analyses keep running while another tab is shown
+		if m.repoModel == nil {
+			return m, nil
+		}
+		model, cmd := m.repoModel.Update(msg)
+		m.repoModel = model
+		return m, cmd
+	case MsgDeveloperCrawlDone:
+		if m.developerModel == nil {
+			return m, nil
+		}
+		model, cmd := m.developerModel.Update(msg)
+		m.developerModel = model
+		return m, cmd
+	case tea.WindowSizeMsg:
+		// keep the inactive tab in sync so switching back renders with the new size
+		if m.repoModel != nil {
+			m.repoModel, _ = m.repoModel.Update(msg)
+		}
+		if m.developerModel != nil {
+			m.developerModel, _ = m.developerModel.Update(msg)
+		}
+		if m.savedModel != nil {
+			m.savedModel, _ = m.savedModel.Update(msg)
+		}
+		return m, nil
 	default:
 		switch m.activeView {
+		case savedView:
+			model, cmd := m.savedModel.Update(msg)
+			m.savedModel = model
+			return m, cmd
+		case developerView:
+			model, cmd := m.developerModel.Update(msg)
+			m.developerModel = model
+			return m, cmd
 		case repoView:
 			model, cmd := m.repoModel.Update(msg)
 			m.repoModel = model
@@ -55,9 +121,20 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// tearDown stops the analyses of the repo view
+func (m MainModel) tearDown() {
+	if r, ok := m.repoModel.(repoModel); ok {
+		r.tearDown()
+	}
+}
+
 // implement the mdoel interface
 func (m MainModel) View() string {
 	switch m.activeView {
+	case savedView:
+		return m.savedModel.View()
+	case developerView:
+		return m.developerModel.View()
 	case repoView:
 		return m.repoModel.View()
 	case fetchView:
@@ -75,9 +152,19 @@ func NewModel() MainModel {
 	}
 }
 
-func askAI(repoUrl string, channel chan *service.ChatResponse) {
+// aiConcurrency is how many analyses run at the same time in the repo view
+var aiConcurrency = 3
+
+// SetConcurrency sets how many analyses run at the same time, non positive values are ignored.
+func SetConcurrency(n int) {
+	if n > 0 {
+		aiConcurrency = n
+	}
+}
+
+func askAI(ctx context.Context, repoUrl string, channel chan *service.ChatResponse, partial func(answer string)) {
 	slog.Debug("ask ai", slog.String("repoUrl", repoUrl))
-	ai, err := service.Chat(context.Background(), repoUrl,3)
+	ai, err := service.ChatStream(ctx, repoUrl, 3, partial)
 	if err != nil {
 		slog.Error("ask ai error", slog.String("repoUrl", repoUrl),
 			slog.String("original error", fmt.Sprintf("%T %V", errors.Cause(err), errors.Cause(err))),
@@ -86,6 +173,9 @@ func askAI(repoUrl string, channel chan *service.ChatResponse) {
 		channel <- ai
 		return
 	}
+	if err := service.SaveAnalysis(repoUrl, ai); err != nil {
+		slog.Error("save ai analysis error", slog.String("repoUrl", repoUrl), slog.String("error", err.Error()))
+	}
 	channel <- ai
 	slog.Debug("ask ai success", slog.String("repoUrl", repoUrl))
 }
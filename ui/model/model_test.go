@@ -0,0 +1,59 @@
+package model
+
+import (
+	"gitoday/global"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pkg/errors"
+)
+
+func TestDevelopersCrawledAgainAfterError(t *testing.T) {
+	var m MainModel
+	open := func() bool {
+		model, cmd := m.Update(MsgSwitchView{View: developerView})
+		m = model.(MainModel)
+		return cmd != nil
+	}
+	if !open() {
+		t.Fatal("developers are not crawled when the tab is opened")
+	}
+	model, _ := m.Update(MsgDeveloperCrawlDone{Error: errors.New("boom")})
+	m = model.(MainModel)
+	m.activeView = repoView
+	if !open() {
+		t.Error("a failed crawl is not tried again")
+	}
+	if d := m.developerModel.(developerModel); d.error != nil {
+		t.Errorf("the new crawl still shows %v", d.error)
+	}
+
+	model, _ = m.Update(MsgDeveloperCrawlDone{})
+	m = model.(MainModel)
+	if open() {
+		t.Error("loaded developers are crawled again")
+	}
+}
+
+func TestQuitDevelopers(t *testing.T) {
+	press := func(k tea.KeyMsg) tea.Msg {
+		m := MainModel{activeView: developerView, developerModel: newDeveloperModel(global.Daily)}
+		_, cmd := m.Update(k)
+		if cmd == nil {
+			t.Fatalf("%s does nothing on the developers tab", k)
+		}
+		msg := cmd()
+		// the message the tab returns is routed through the main model again
+		if _, ok := msg.(MsgQuitRepoView); ok {
+			_, cmd = m.Update(msg)
+			msg = cmd()
+		}
+		return msg
+	}
+	if msg := press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); msg != (MsgRestart{}) {
+		t.Errorf("q leads to %#v, want a restart", msg)
+	}
+	if msg := press(tea.KeyMsg{Type: tea.KeyCtrlC}); msg != tea.Quit() {
+		t.Errorf("ctrl+c leads to %#v, want quit", msg)
+	}
+}
@@ -1,13 +1,19 @@
 package model
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"gitoday/global"
 	"gitoday/service"
+	"gitoday/storage"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -16,23 +22,54 @@ import (
 )
 
 type repoModel struct {
-	repoList      list.Model
-	repoDetail    viewport.Model
-	keyMap        list.KeyMap
-	repoListItems []*repoItem
-	mapAiChannel  map[string]chan *service.ChatResponse
+	title        string
+	sort         repoSort
+	repoList     list.Model
+	repoDetail   viewport.Model
+	keyMap       list.KeyMap
+	mapAiChannel map[string]chan *service.ChatResponse
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// workers bounds how many analyses are in flight
+	workers chan struct{}
+	// queued are the repos of the running "analyse all", analysed counts the finished ones
+	queued   map[string]bool
+	analysed int
+	// partialCh streams the answers being generated, partials keeps the latest of each repo
+	partialCh chan MsgAIPartial
+	partials  map[string]string
+	// items are all repositories, the list shows them through the newcomer filter
+	// and the search. Changes to an item are made here and in the list.
+	items     []list.Item
+	newcomers bool
+	// search is the query, searching while it is typed
+	search    textinput.Model
+	searching bool
 }
 
 func (m repoModel) Init() tea.Cmd {
-	return nil
+	return m.waitPartial()
 }
 
-func (m *repoModel) tearDown() {
-	for _, v := range m.mapAiChannel {
-		close(v)
+// waitPartial delivers the next partial answer, it is issued again after each one
+func (m repoModel) waitPartial() tea.Cmd {
+	ctx, partialCh := m.ctx, m.partialCh
+	return func() tea.Msg {
+		select {
+		case p := <-partialCh:
+			return p
+		case <-ctx.Done():
+			return nil
+		}
 	}
 }
 
+func (m *repoModel) tearDown() {
+	// analyses still in flight write to their buffered channel and are dropped
+	m.cancel()
+}
+
 func (m *repoModel) updateSize() {
 	m.repoList.SetHeight(getRepoListHeight())
 	m.repoList.SetWidth(getRepoListWidth())
@@ -46,12 +83,23 @@ func (m repoModel) Update(tmsg tea.Msg) (tea.Model, tea.Cmd) {
 		setTerminalSize(msg.Width, msg.Height)
 		m.updateSize()
 		return m, nil
-	case MsgQuitRepoView:
-		m.tearDown()
-		return m, EventRestart()
 	case MsgAIFinish:
-		return m, nil
+		return m, m.finishAI(msg.Url)
+	case MsgAIPartial:
+		for _, item := range m.repoList.Items() {
+			if r := item.(repoItem); r.Url == msg.Url && r.AIProcess == InProgress {
+				m.partials[msg.Url] = msg.Answer
+				if selected := m.repoList.SelectedItem(); selected != nil && selected.(repoItem).Url == msg.Url {
+					m.repoDetail.SetContent(getRepoDetailContent(r, msg.Answer))
+					m.repoDetail.GotoBottom()
+				}
+			}
+		}
+		return m, m.waitPartial()
 	case tea.KeyMsg:
+		if m.searching {
+			return m.updateSearch(msg)
+		}
 		switch {
 		case key.Matches(msg, m.keyMap.CursorUp):
 			m.repoList.CursorUp()
@@ -60,25 +108,104 @@ func (m repoModel) Update(tmsg tea.Msg) (tea.Model, tea.Cmd) {
 			m.repoList.CursorDown()
 			return show(&m)
 		}
-		switch msg.String() {
-		case "enter":
+		switch {
+		case key.Matches(msg, repoKeys.Search):
+			return m, m.startSearch()
+		case key.Matches(msg, repoKeys.Analyze):
 			selected := m.repoList.SelectedItem()
-			var r repoItem
-			err := json.Unmarshal([]byte(selected.FilterValue()), &r)
-			if err != nil {
-				slog.Error("json unmarshal error when press enter",
-					slog.String("error", fmt.Sprintf("%T %v", errors.Cause(err), errors.Cause(err))))
+			if selected == nil {
 				return m, nil
 			}
+			r := selected.(repoItem)
 			if r.AIProcess == Failed || r.AIProcess == Ready {
 				r.AIProcess = InProgress
-				go askAI(r.Url, m.mapAiChannel[r.Url])
-				m.repoDetail.SetContent(getRepoDetailContent(r))
-				return m, m.repoList.SetItem(m.repoList.Index(), r)
+				m.repoDetail.SetContent(getRepoDetailContent(r, ""))
+				return m, tea.Batch(m.setItem(r), m.analyze(r.Url))
 			}
 			return m, nil
-		case "ctrl+c", "esc", "q":
-			// Exit the program
+		case key.Matches(msg, repoKeys.AnalyzeAll):
+			return m, m.analyzeAll()
+		case key.Matches(msg, repoKeys.Refresh):
+			// force a new analysis even if the answer came from the cache
+			selected := m.repoList.SelectedItem()
+			if selected == nil {
+				return m, nil
+			}
+			r := selected.(repoItem)
+			if r.AIProcess == InProgress {
+				return m, nil
+			}
+			r.AIProcess = InProgress
+			r.AIAnswer = ""
+			m.repoDetail.SetContent(getRepoDetailContent(r, ""))
+			return m, tea.Batch(m.setItem(r), m.analyze(r.Url))
+		case key.Matches(msg, repoKeys.Developers):
+			return m, EventSwitchView(developerView)
+		case key.Matches(msg, repoKeys.Newcomers):
+			m.newcomers = !m.newcomers
+			if !m.newcomers {
+				return m, m.refresh(m.selectedUrl())
+			}
+			var count int
+			for _, item := range m.items {
+				if item.(repoItem).New {
+					count++
+				}
+			}
+			return m, tea.Batch(m.refresh(""), m.repoList.NewStatusMessage(tr(msgNewcomers, count)))
+		case key.Matches(msg, repoKeys.Open):
+			if selected := m.repoList.SelectedItem(); selected != nil {
+				url := selected.(repoItem).Url
+				if err := openBrowser(url); err != nil {
+					return m, m.repoList.NewStatusMessage(failedStatusStyle.Render(err.Error()))
+				}
+				return m, m.repoList.NewStatusMessage(statusStyle.Render(tr(msgOpened, url)))
+			}
+			return m, nil
+		case key.Matches(msg, repoKeys.CopyUrl, repoKeys.CopyClone):
+			if selected := m.repoList.SelectedItem(); selected != nil {
+				text := selected.(repoItem).Url
+				if key.Matches(msg, repoKeys.CopyClone) {
+					text = cloneCommand(text)
+				}
+				if err := copyToClipboard(text); err != nil {
+					return m, m.repoList.NewStatusMessage(failedStatusStyle.Render(err.Error()))
+				}
+				return m, m.repoList.NewStatusMessage(statusStyle.Render(tr(msgCopied, text)))
+			}
+			return m, nil
+		case key.Matches(msg, repoKeys.Bookmark):
+			selected := m.repoList.SelectedItem()
+			if selected == nil {
+				return m, nil
+			}
+			r := selected.(repoItem)
+			b := storage.Bookmark{Name: r.Name, Url: r.Url, Desc: r.Desc, Lang: r.Lang}
+			if r.AIProcess == Success {
+				b.Analysis = &service.ChatResponse{}
+				_ = json.Unmarshal([]byte(r.AIAnswer), b.Analysis)
+			}
+			saved, err := storage.ToggleBookmark(b)
+			if err != nil {
+				return m, m.repoList.NewStatusMessage(failedStatusStyle.Render(err.Error()))
+			}
+			r.Bookmarked = saved
+			status := tr(msgUnbookmarked, r.Name)
+			if saved {
+				status = tr(msgBookmarked, r.Name)
+			}
+			return m, tea.Batch(m.setItem(r), m.repoList.NewStatusMessage(statusStyle.Render(status)))
+		case key.Matches(msg, repoKeys.Sort):
+			m.sort = repoSort{key: m.sort.key.next(), ascending: m.sort.key.next().ascending()}
+			return m, m.applySort()
+		case key.Matches(msg, repoKeys.Reverse):
+			m.sort.ascending = !m.sort.ascending
+			return m, m.applySort()
+		case key.Matches(msg, repoKeys.Quit):
+			// a search is closed first, then back to the chooser
+			if m.searchActive() {
+				return m, m.clearSearch()
+			}
 			return m, EventQuitRepoView()
 		}
 	}
@@ -87,47 +214,86 @@ func (m repoModel) Update(tmsg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m repoModel) View() string {
 	repoListView := m.repoList.View()
+	if m.searchActive() {
+		count := tr(msgSearchCount, len(m.repoList.Items()), len(m.items))
+		repoListView += "\n" + m.search.View() + " " + statusStyle.Render(count)
+	}
 	detailView := m.repoDetail.View()
 	content := lipgloss.JoinHorizontal(
 		lipgloss.Top,
 		repoListStyle.Render(repoListView),
 		detailView,
 	)
+	if len(m.queued) > 0 {
+		total := m.analysed + len(m.queued)
+		progress := tr(msgAnalysing, emoji.Robot, m.analysed, total, progressbar(float64(m.analysed)/float64(total)))
+		return lipgloss.JoinVertical(lipgloss.Left, progress, content)
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, content)
 }
 
-func newRepoModel(repos []*service.Repo) repoModel {
+func newRepoModel(repos []*service.Repo, diff []service.RepoDiff, since global.Since) repoModel {
 	jobItems := make([]list.Item, len(repos))
-	r := makeRepoItem(repos)
+	r := makeRepoItem(repos, diff)
+	// the list holds values, the key handlers read the selected item back as a repoItem
 	for i, repo := range r {
-		jobItems[i] = repo
+		jobItems[i] = *repo
 	}
 
-	l := list.New(jobItems, newAppItemDelegate(), getRepoListWidth(), getRepoListHeight())
+	l := list.New(jobItems, newRepoItemDelegate(), getRepoListWidth(), getRepoListHeight())
 
-	l.Title = fmt.Sprintf("%v Top Repositories %v", emoji.Rocket, emoji.Rocket)
+	title := tr(msgTrending, emoji.Rocket, sinceLabel(since))
+	l.Title = title
+	l.AdditionalShortHelpKeys = repoKeys.shortHelp
 	mapAiChannel := map[string]chan *service.ChatResponse{}
 	for _, r := range repos {
 		mapAiChannel[r.Url] = make(chan *service.ChatResponse, 1)
 	}
+	l.StatusMessageLifetime = 3 * time.Second
+	// the list filter matches the whole item and its keys are taken, the search replaces it
+	l.SetFilteringEnabled(false)
 	if len(jobItems) > 0 {
 		l.Select(0)
 	}
+	search := textinput.New()
+	search.Prompt = fmt.Sprintf("%v ", emoji.MagnifyingGlassTiltedLeft)
+	search.Placeholder = tr(msgSearchPlaceholder)
+	ctx, cancel := context.WithCancel(context.Background())
 	return repoModel{
-		repoList:      l,
-		repoListItems: r,
+		title:    title,
+		sort:     repoSort{key: sortByRank, ascending: true},
+		repoList: l,
 		repoDetail: viewport.Model{
 			Width:  getRepoDetailWidth(),
 			Height: getRepoDetailHeight(),
 		},
 		keyMap:       list.DefaultKeyMap(),
 		mapAiChannel: mapAiChannel,
+		ctx:          ctx,
+		cancel:       cancel,
+		workers:      make(chan struct{}, aiConcurrency),
+		queued:       map[string]bool{},
+		partialCh:    make(chan MsgAIPartial, 64),
+		partials:     map[string]string{},
+		items:        jobItems,
+		search:       search,
 	}
 }
 
-func makeRepoItem(repo []*service.Repo) []*repoItem {
+func makeRepoItem(repo []*service.Repo, diff []service.RepoDiff) []*repoItem {
 	items := make([]*repoItem, len(repo))
+	bookmarked := map[string]bool{}
+	bookmarks, _ := storage.Bookmarks()
+	for _, b := range bookmarks {
+		bookmarked[b.Url] = true
+	}
 	for i, r := range repo {
+		status, answer := Ready, ""
+		if cached, ok := service.LoadAnalysis(r.Url); ok {
+			if b, err := json.Marshal(cached); err == nil {
+				status, answer = Success, string(b)
+			}
+		}
 		items[i] = &repoItem{
 			Index:     i,
 			Name:      r.Name,
@@ -137,65 +303,260 @@ func makeRepoItem(repo []*service.Repo) []*repoItem {
 			Star:      r.Star,
 			Fork:      r.Fork,
 			TodayStar: r.TodayStar,
-			AIProcess: Ready,
-			AIAnswer:  "",
+			AIProcess: status,
+			AIAnswer:  answer,
+
+			Bookmarked: bookmarked[r.Url],
+
+			StarCount:      r.StarCount,
+			ForkCount:      r.ForkCount,
+			TodayStarCount: r.TodayStarCount,
+
+			Meta: r.Meta,
+		}
+		if diff != nil {
+			items[i].Compared = true
+			items[i].New = diff[i].New
+			items[i].RankDelta = diff[i].RankDelta
+			items[i].StarDelta = diff[i].StarDelta
 		}
 
 	}
 	return items
 }
+
+// applySort reorders the visible items and keeps the selected repository selected
+func (m *repoModel) applySort() tea.Cmd {
+	return m.refresh(m.selectedUrl())
+}
+
+// refresh rebuilds the list from all repositories: the newcomer filter narrows them,
+// then the search ranks the matches or else the sort orders them. The repository
+// with selectedUrl stays selected, the first one is selected when it is not shown.
+func (m *repoModel) refresh(selectedUrl string) tea.Cmd {
+	var items []list.Item
+	for _, item := range m.items {
+		if !m.newcomers || item.(repoItem).New {
+			items = append(items, item)
+		}
+	}
+	if terms := strings.Fields(m.search.Value()); len(terms) > 0 {
+		items = rankItems(items, terms)
+	} else {
+		items = sortItems(items, m.sort)
+	}
+	cmd := m.repoList.SetItems(items)
+	m.repoList.Select(0)
+	for i, item := range items {
+		if item.(repoItem).Url == selectedUrl {
+			m.repoList.Select(i)
+			break
+		}
+	}
+	title := m.title + m.sort.label()
+	if m.newcomers {
+		title += " · " + tr(msgNewcomerTitle)
+	}
+	m.repoList.Title = title
+	if len(items) == 0 && m.searchActive() {
+		m.repoDetail.SetContent(tr(msgNoMatches, emoji.MagnifyingGlassTiltedLeft, m.search.Value()))
+		return cmd
+	}
+	_, showCmd := show(m)
+	return tea.Batch(cmd, showCmd)
+}
+
+func (m *repoModel) selectedUrl() string {
+	if selected := m.repoList.SelectedItem(); selected != nil {
+		return selected.(repoItem).Url
+	}
+	return ""
+}
+
+// setItem stores a changed repository in all repositories and in the list if it is shown
+func (m *repoModel) setItem(r repoItem) tea.Cmd {
+	for i, item := range m.items {
+		if item.(repoItem).Url == r.Url {
+			stored := r
+			stored.search = nil
+			m.items[i] = stored
+		}
+	}
+	for i, item := range m.repoList.Items() {
+		if item.(repoItem).Url == r.Url {
+			return m.repoList.SetItem(i, r)
+		}
+	}
+	return nil
+}
+
 func show(m *repoModel) (tea.Model, tea.Cmd) {
 	selected := m.repoList.SelectedItem()
 	if selected != nil {
-		var r repoItem
-		_ = json.Unmarshal([]byte(selected.FilterValue()), &r)
-		var aiAnswer []byte
+		r := selected.(repoItem)
 		if r.AIProcess == InProgress {
-			ai, err := getAIDetail(m.mapAiChannel, selected.FilterValue())
-			if err != nil {
-				slog.Error("get ai detail error,set AIProcess failed",
-					slog.String("original error", fmt.Sprintf("%T %v", errors.Cause(err), errors.Cause(err))),
-					slog.String("stack", fmt.Sprintf("%+v", err)))
-				r.AIProcess = Failed
-			} else if ai != nil {
-				r.AIProcess = Success
-				aiAnswer, _ = json.Marshal(ai)
-				r.AIAnswer = string(aiAnswer)
-			}
+			resolveAI(m.mapAiChannel, &r)
+		}
+		if r.AIProcess != InProgress {
+			delete(m.partials, r.Url)
 		}
-		m.repoDetail.SetContent(getRepoDetailContent(r))
-		return m, m.repoList.SetItem(m.repoList.Index(), r)
+		m.repoDetail.SetContent(getRepoDetailContent(r, m.partials[r.Url]))
+		return m, m.setItem(r)
 	}
 	return m, nil
 }
-func getRepoDetailContent(r repoItem) string {
-	title := fmt.Sprintf("%v Repository Inspiration %v", emoji.OncomingFist, emoji.OncomingFist)
-	name := fmt.Sprintf("%v %s ", emoji.TwoHearts, r.Name)
+
+// resolveAI moves an in progress repo to Success or Failed once its answer arrived
+func resolveAI(responseChans map[string]chan *service.ChatResponse, r *repoItem) {
+	ai, err := getAIDetail(responseChans, r.Url)
+	if err != nil {
+		slog.Error("get ai detail error,set AIProcess failed",
+			slog.String("original error", fmt.Sprintf("%T %v", errors.Cause(err), errors.Cause(err))),
+			slog.String("stack", fmt.Sprintf("%+v", err)))
+		r.AIProcess = Failed
+		r.AIError = err.Error()
+		r.AIHint = aiErrorHint(err)
+	} else if ai != nil {
+		r.AIProcess = Success
+		aiAnswer, _ := json.Marshal(ai)
+		r.AIAnswer = string(aiAnswer)
+		if r.Bookmarked {
+			if err := storage.SetBookmarkAnalysis(r.Url, ai); err != nil {
+				slog.Error("save bookmark analysis error", slog.String("repoUrl", r.Url), slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// analyze asks AI about the repo once a worker is free, MsgAIFinish reports back
+func (m *repoModel) analyze(url string) tea.Cmd {
+	delete(m.partials, url)
+	ctx, workers, channel, partialCh := m.ctx, m.workers, m.mapAiChannel[url], m.partialCh
+	partial := func(answer string) {
+		select {
+		case partialCh <- MsgAIPartial{Url: url, Answer: answer}:
+		default:
+			// the UI is behind, a later partial carries this text as well
+		}
+	}
+	return func() tea.Msg {
+		select {
+		case workers <- struct{}{}:
+		case <-ctx.Done():
+			return nil
+		}
+		defer func() { <-workers }()
+		askAI(ctx, url, channel, partial)
+		return MsgAIFinish{Url: url}
+	}
+}
+
+// analyzeAll queues every visible Ready or Failed repo
+func (m *repoModel) analyzeAll() tea.Cmd {
+	visible := map[string]bool{}
+	for _, item := range m.repoList.VisibleItems() {
+		visible[item.(repoItem).Url] = true
+	}
+	var cmds []tea.Cmd
+	for _, item := range m.repoList.Items() {
+		r := item.(repoItem)
+		if !visible[r.Url] || (r.AIProcess != Ready && r.AIProcess != Failed) {
+			continue
+		}
+		r.AIProcess = InProgress
+		m.queued[r.Url] = true
+		cmds = append(cmds, m.setItem(r), m.analyze(r.Url))
+	}
+	if len(cmds) == 0 {
+		return m.repoList.NewStatusMessage(statusStyle.Render(tr(msgNothingToAnalyse)))
+	}
+	_, showCmd := show(m)
+	return tea.Batch(append(cmds, showCmd)...)
+}
+
+// finishAI updates the status of the finished repo, shown or not, and the progress
+// of "analyse all"
+func (m *repoModel) finishAI(url string) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, item := range m.items {
+		r := item.(repoItem)
+		if r.Url != url || r.AIProcess != InProgress {
+			continue
+		}
+		resolveAI(m.mapAiChannel, &r)
+		delete(m.partials, url)
+		cmds = append(cmds, m.setItem(r))
+		if m.selectedUrl() == url {
+			r.search = strings.Fields(m.search.Value())
+			m.repoDetail.SetContent(getRepoDetailContent(r, ""))
+		}
+	}
+	if m.queued[url] {
+		delete(m.queued, url)
+		m.analysed++
+		if len(m.queued) == 0 {
+			cmds = append(cmds, m.repoList.NewStatusMessage(statusStyle.Render(tr(msgAnalysed, m.analysed))))
+			m.analysed = 0
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// getRepoDetailContent renders the repo, partial is the answer streamed so far while AI is analysing
+func getRepoDetailContent(r repoItem, partial string) string {
+	title := tr(msgDetailTitle, emoji.OncomingFist, emoji.OncomingFist)
+	name := fmt.Sprintf("%v %s ", emoji.TwoHearts, highlight(r.Name, r.search))
 	url := fmt.Sprintf("%v %s", emoji.Link, r.Url)
-	des := fmt.Sprintf("%v %s", emoji.OpenBook, r.Desc)
+	des := fmt.Sprintf("%v %s", emoji.OpenBook, highlight(r.Desc, r.search))
 	var aiAnswer string
 	switch r.AIProcess {
 	case InProgress:
-		aiAnswer = fmt.Sprintf("%v AI is analyzing the project,please waiting...%v", emoji.Robot, emoji.TimerClock)
+		aiAnswer = tr(msgAIInProgress, emoji.Robot, emoji.TimerClock)
+		if partial != "" {
+			aiAnswer += "\n\n" + wrapText(partial, uint(getRepoDetailWidth()-4))
+		}
 	case Failed:
-		aiAnswer = fmt.Sprintf("%v AI is tired,please press [ENTER] to retry later.", emoji.TiredFace)
+		aiAnswer = tr(msgAIFailed, emoji.TiredFace)
+		if r.AIError != "" {
+			aiAnswer += "\n\n" + failedStatusStyle.Render(wrapText(r.AIError, uint(getRepoDetailWidth()-4)))
+		}
+		if r.AIHint != "" {
+			aiAnswer += "\n\n" + wrapText(r.AIHint, uint(getRepoDetailWidth()-4))
+		}
 	case Success:
-		aiAnswer = fmt.Sprintf("%v AI analyse finished %v\n\n%s", emoji.FastDownButton, emoji.FastDownButton, formatAI(r.AIAnswer))
+		aiAnswer = tr(msgAISuccess, emoji.FastDownButton, emoji.FastDownButton, highlight(formatAI(r.AIAnswer), r.search))
 	case Ready:
-		aiAnswer = fmt.Sprintf("%v Press [ENTER] to unlock AI Power %v", emoji.Locked, emoji.Robot)
+		aiAnswer = tr(msgAIReady, emoji.Locked, emoji.Robot)
 	default:
-		aiAnswer = fmt.Sprintf("%v Press [ENTER] to unlock AI Power %v", emoji.Locked, emoji.Robot)
+		aiAnswer = tr(msgAIReady, emoji.Locked, emoji.Robot)
 	}
-	return fmt.Sprintf("%s\n\n%s\n\n%s\n", title, name, url) + "\n" + des + "\n\n\n" + aiAnswer
+	return fmt.Sprintf("%s\n\n%s\n\n%s\n", title, name, url) + "\n" + des + formatMeta(r.Meta) + "\n\n\n" + aiAnswer
 }
-func getAIDetail(responseChans map[string]chan *service.ChatResponse, data string) (*service.ChatResponse, error) {
-	var r repoItem
-	err := json.Unmarshal([]byte(data), &r)
-	if err != nil {
-		err = errors.Wrap(err, "json unmarshal error")
-		return nil, err
+
+// formatMeta renders what the GitHub API enrichment added, nothing when it is off
+func formatMeta(m *service.RepoMeta) string {
+	if m == nil {
+		return ""
+	}
+	var lines []string
+	if m.Archived {
+		lines = append(lines, tr(msgArchived, emoji.Warning))
+	}
+	if len(m.Topics) > 0 {
+		lines = append(lines, wrapText(fmt.Sprintf("%v %s", emoji.Label, strings.Join(m.Topics, ", ")), uint(getRepoDetailWidth()-4)))
 	}
-	responseChan := responseChans[r.Url]
+	if m.License != "" {
+		lines = append(lines, fmt.Sprintf("%v %s", emoji.Scroll, m.License))
+	}
+	if m.Homepage != "" {
+		lines = append(lines, fmt.Sprintf("%v %s", emoji.House, m.Homepage))
+	}
+	lines = append(lines, tr(msgActivity, emoji.Calendar,
+		m.CreatedAt.Format("2006-01-02"), m.PushedAt.Format("2006-01-02"), m.OpenIssues))
+	return "\n\n" + strings.Join(lines, "\n")
+}
+func getAIDetail(responseChans map[string]chan *service.ChatResponse, url string) (*service.ChatResponse, error) {
+	responseChan := responseChans[url]
 	select {
 	case res := <-responseChan:
 		if res.Error != nil {
@@ -211,17 +572,71 @@ func formatAI(answer string) string {
 	a := &service.ChatResponse{}
 	json.Unmarshal([]byte(answer), a)
 
-	why := fmt.Sprintf("%v %s\n", emoji.QuestionMark, "WHY")
+	why := fmt.Sprintf("%v %s\n", emoji.QuestionMark, tr(msgWhy))
 	for _, v := range a.Why {
 		why += wrapText(fmt.Sprintf("%v %s\n", emoji.RedCircle, v), uint(getRepoDetailWidth()-4))
 	}
-	how := fmt.Sprintf("%v %s:\n", emoji.Hammer, "HOW")
+	how := fmt.Sprintf("%v %s:\n", emoji.Hammer, tr(msgHow))
 	for _, v := range a.How {
 		how += wrapText(fmt.Sprintf("%v %s\n", emoji.BlueCircle, v), uint(getRepoDetailWidth()-4))
 	}
-	others := fmt.Sprintf("%v %s:\n", emoji.BarChart, "MORE")
+	others := fmt.Sprintf("%v %s:\n", emoji.BarChart, tr(msgMore))
 	for _, v := range a.Other {
 		others += wrapText(fmt.Sprintf("%v %s\n", emoji.GreenCircle, v), uint(getRepoDetailWidth()-4))
 	}
 	return fmt.Sprintf("%s\n\n%s\n\n%s", why, how, others)
 }
+
+// searchActive tells whether the query is being typed or narrows the list
+func (m repoModel) searchActive() bool {
+	return m.searching || m.search.Value() != ""
+}
+
+// startSearch focuses the query input, a search already narrowing the list is edited
+func (m *repoModel) startSearch() tea.Cmd {
+	m.searching = true
+	m.search.CursorEnd()
+	return m.search.Focus()
+}
+
+// updateSearch edits the query, the list narrows with every key and the arrows move
+// through the matches. Enter keeps the matches, esc brings every repository back.
+func (m repoModel) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return m, m.clearSearch()
+	case "enter":
+		if strings.TrimSpace(m.search.Value()) == "" {
+			return m, m.clearSearch()
+		}
+		m.searching = false
+		m.search.Blur()
+		return m, nil
+	case "up":
+		m.repoList.CursorUp()
+		return show(&m)
+	case "down":
+		m.repoList.CursorDown()
+		return show(&m)
+	}
+	query := m.search.Value()
+	var cmd tea.Cmd
+	m.search, cmd = m.search.Update(msg)
+	if m.search.Value() == query {
+		return m, cmd
+	}
+	return m, tea.Batch(cmd, m.applySearch())
+}
+
+// applySearch shows the repositories matching the query, best first
+func (m *repoModel) applySearch() tea.Cmd {
+	return m.refresh("")
+}
+
+// clearSearch brings every repository back in the current sort order
+func (m *repoModel) clearSearch() tea.Cmd {
+	m.searching = false
+	m.search.Blur()
+	m.search.SetValue("")
+	return m.applySort()
+}
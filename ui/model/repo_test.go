@@ -0,0 +1,275 @@
+package model
+
+import (
+	"context"
+	"gitoday/global"
+	"gitoday/service"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pkg/errors"
+)
+
+// blockingProvider answers once released and records how many questions were in flight at most
+type blockingProvider struct {
+	release        chan struct{}
+	inFlight, peak atomic.Int32
+}
+
+func (p *blockingProvider) Name() string { return "blocking" }
+
+func (p *blockingProvider) Ask(ctx context.Context, query string) (string, error) {
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	select {
+	case <-p.release:
+		return `{"what":"a tool","why":["fast"],"how":["go"]}`, nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func TestAnalyzeAll(t *testing.T) {
+	const concurrency = 2
+	SetConcurrency(concurrency)
+	defer SetConcurrency(3)
+	provider := &blockingProvider{release: make(chan struct{})}
+	// the GitHub API knows no repository, the prompt goes without README
+	client := service.NewClient(&http.Client{Transport: notFound{}})
+	client.SetProvider(provider)
+	old := service.DefaultClient()
+	service.SetClient(client)
+	defer service.SetClient(old)
+
+	var repos []*service.Repo
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		repos = append(repos, &service.Repo{Name: name, Url: "https://www.github.com/o/" + name})
+	}
+	m := newRepoModel(repos, nil, global.Daily)
+	defer m.tearDown()
+	m.analyzeAll()
+	if len(m.queued) != len(repos) {
+		t.Fatalf("queued %d repos, want %d", len(m.queued), len(repos))
+	}
+	msgs := make(chan tea.Msg, len(repos))
+	for _, r := range repos {
+		go func(cmd tea.Cmd) { msgs <- cmd() }(m.analyze(r.Url))
+	}
+	for provider.inFlight.Load() < concurrency {
+		time.Sleep(time.Millisecond)
+	}
+	// give the analyses waiting for a worker the chance to slip through
+	time.Sleep(50 * time.Millisecond)
+	close(provider.release)
+	for range repos {
+		msg := <-msgs
+		finish, ok := msg.(MsgAIFinish)
+		if !ok {
+			t.Fatalf("unexpected message %#v", msg)
+		}
+		m.finishAI(finish.Url)
+	}
+	if peak := provider.peak.Load(); peak > concurrency {
+		t.Errorf("%d analyses ran at the same time, want at most %d", peak, concurrency)
+	}
+	for _, item := range m.repoList.Items() {
+		if r := item.(repoItem); r.AIProcess != Success {
+			t.Errorf("%s status = %d, want success", r.Name, r.AIProcess)
+		}
+	}
+	if len(m.queued) != 0 || m.analysed != 0 {
+		t.Errorf("progress not reset, queued %d analysed %d", len(m.queued), m.analysed)
+	}
+	// nothing is left to analyse, so nothing is queued again
+	m.analyzeAll()
+	if len(m.queued) != 0 {
+		t.Errorf("queued %d finished repos", len(m.queued))
+	}
+}
+
+type notFound struct{}
+
+func (notFound) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: http.NoBody, Request: r}, nil
+}
+
+func TestPartialAnswer(t *testing.T) {
+	repos := []*service.Repo{{Name: "a", Url: "https://www.github.com/o/a"}}
+	m := newRepoModel(repos, nil, global.Daily)
+	defer m.tearDown()
+	// partials of a repo that is not being analysed are dropped
+	model, _ := m.Update(MsgAIPartial{Url: repos[0].Url, Answer: `{"what":`})
+	m = model.(repoModel)
+	if len(m.partials) != 0 {
+		t.Fatalf("kept a partial of an idle repo %v", m.partials)
+	}
+
+	r := m.repoList.Items()[0].(repoItem)
+	r.AIProcess = InProgress
+	m.repoList.SetItem(0, r)
+	model, _ = m.Update(MsgAIPartial{Url: repos[0].Url, Answer: `{"what":"a tool"`})
+	m = model.(repoModel)
+	if !strings.Contains(m.repoDetail.View(), "a tool") {
+		t.Errorf("partial answer is not rendered:\n%s", m.repoDetail.View())
+	}
+}
+
+func TestFailedAnswerShowsError(t *testing.T) {
+	repos := []*service.Repo{{Name: "a", Url: "https://www.github.com/o/a"}}
+	m := newRepoModel(repos, nil, global.Daily)
+	defer m.tearDown()
+	r := m.repoList.Items()[0].(repoItem)
+	r.AIProcess = InProgress
+	m.repoList.SetItem(0, r)
+	err := &service.SchemaError{Problems: []string{"why is missing"}}
+	m.mapAiChannel[r.Url] <- &service.ChatResponse{Error: err}
+	m.finishAI(r.Url)
+
+	if got := m.repoList.Items()[0].(repoItem); got.AIProcess != Failed || got.AIError != err.Error() {
+		t.Fatalf("unexpected item %+v", got)
+	}
+	if !strings.Contains(m.repoDetail.View(), "why is missing") {
+		t.Errorf("validation error is not shown:\n%s", m.repoDetail.View())
+	}
+	if got := m.repoList.Items()[0].(repoItem).AIHint; got != tr(msgHintAIParse) {
+		t.Errorf("unexpected hint %q", got)
+	}
+}
+
+func TestAIErrorHint(t *testing.T) {
+	cases := []struct {
+		err  error
+		want string
+	}{
+		{&service.StatusError{StatusCode: 429}, tr(msgHintAIRateLimited)},
+		{errors.Wrap(&service.StatusError{StatusCode: 401}, "ask"), tr(msgHintAIAuth)},
+		{service.ErrTimeout, tr(msgHintAITimeout)},
+		{errors.New("boom"), ""},
+	}
+	for _, c := range cases {
+		if got := aiErrorHint(c.err); got != c.want {
+			t.Errorf("aiErrorHint(%v) = %q, want %q", c.err, got, c.want)
+		}
+	}
+}
+
+func TestSearchMode(t *testing.T) {
+	repos := []*service.Repo{
+		{Name: "neovim/neovim", Url: "https://www.github.com/neovim/neovim", Desc: "Vim-fork focused on extensibility"},
+		{Name: "astral-sh/uv", Url: "https://www.github.com/astral-sh/uv", Desc: "fast python package manager", Lang: "Rust"},
+		{Name: "o/vimrc", Url: "https://www.github.com/o/vimrc", Desc: "dotfiles"},
+	}
+	m := newRepoModel(repos, nil, global.Daily)
+	defer m.tearDown()
+	press := func(keys ...tea.KeyMsg) {
+		for _, k := range keys {
+			model, _ := m.Update(k)
+			m = model.(repoModel)
+		}
+	}
+	typed := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+	uv := m.repoList.Items()[1].(repoItem)
+	uv.AIProcess = InProgress
+	m.repoList.SetItem(1, uv)
+
+	press(typed("/"), typed("v"), typed("i"), typed("m"))
+	var names []string
+	for _, item := range m.repoList.Items() {
+		names = append(names, item.(repoItem).Name)
+	}
+	if strings.Join(names, ",") != "o/vimrc,neovim/neovim" {
+		t.Fatalf("search vim shows %v", names)
+	}
+	// the analysis of a repo the search hides finishes
+	m.mapAiChannel[uv.Url] <- &service.ChatResponse{What: "a package manager"}
+	m.finishAI(uv.Url)
+	// q is part of the query while typing and closes the search afterwards
+	press(tea.KeyMsg{Type: tea.KeyEnter}, typed("q"))
+	if m.searchActive() || len(m.repoList.Items()) != len(repos) {
+		t.Fatalf("search is not cleared, %d items", len(m.repoList.Items()))
+	}
+	for _, item := range m.repoList.Items() {
+		r := item.(repoItem)
+		if r.search != nil {
+			t.Errorf("%s keeps the search terms", r.Name)
+		}
+		if r.Url == uv.Url && r.AIProcess != Success {
+			t.Errorf("%s status = %d after the search, want success", r.Name, r.AIProcess)
+		}
+	}
+
+	press(typed("/"), typed("x"), typed("y"), typed("z"))
+	if len(m.repoList.Items()) != 0 || !strings.Contains(m.repoDetail.View(), "xyz") {
+		t.Errorf("expected no match for xyz:\n%s", m.repoDetail.View())
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if len(m.repoList.Items()) != len(repos) || m.searching {
+		t.Errorf("esc does not restore the list")
+	}
+}
+
+func TestNewcomersAndSearch(t *testing.T) {
+	repos := []*service.Repo{
+		{Name: "neovim/neovim", Url: "https://www.github.com/neovim/neovim"},
+		{Name: "o/vimrc", Url: "https://www.github.com/o/vimrc"},
+		{Name: "astral-sh/uv", Url: "https://www.github.com/astral-sh/uv"},
+		{Name: "o/dotfiles", Url: "https://www.github.com/o/dotfiles"},
+	}
+	diff := []service.RepoDiff{{}, {New: true}, {New: true}, {}}
+	m := newRepoModel(repos, diff, global.Daily)
+	defer m.tearDown()
+	press := func(keys ...string) {
+		for _, k := range keys {
+			msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+			if k == "enter" {
+				msg = tea.KeyMsg{Type: tea.KeyEnter}
+			}
+			model, _ := m.Update(msg)
+			m = model.(repoModel)
+		}
+	}
+	names := func() string {
+		var names []string
+		for _, item := range m.repoList.Items() {
+			names = append(names, item.(repoItem).Name)
+		}
+		return strings.Join(names, ",")
+	}
+	uv := m.repoList.Items()[2].(repoItem)
+	uv.AIProcess = InProgress
+	m.repoList.SetItem(2, uv)
+	m.setItem(uv)
+
+	press("/", "v", "i", "m", "enter", "n")
+	if got := names(); got != "o/vimrc" {
+		t.Fatalf("newcomers matching vim are %s", got)
+	}
+	// the analysis of a repo hidden by both finishes
+	m.mapAiChannel[uv.Url] <- &service.ChatResponse{What: "a package manager"}
+	m.finishAI(uv.Url)
+
+	press("q")
+	if got := names(); got != "o/vimrc,astral-sh/uv" {
+		t.Fatalf("newcomers without the search are %s", got)
+	}
+	if r := m.repoList.Items()[1].(repoItem); r.AIProcess != Success {
+		t.Errorf("%s status = %d, want success", r.Name, r.AIProcess)
+	}
+	press("n")
+	if got := names(); got != "neovim/neovim,o/vimrc,astral-sh/uv,o/dotfiles" {
+		t.Fatalf("all repositories are %s", got)
+	}
+	if strings.Contains(m.repoList.Title, tr(msgNewcomerTitle)) {
+		t.Errorf("title %q still says newcomers", m.repoList.Title)
+	}
+}
@@ -0,0 +1,245 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"gitoday/storage"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/enescakir/emoji"
+)
+
+type savedItem struct {
+	storage.Bookmark
+}
+
+func (s savedItem) Title() string {
+	return fmt.Sprintf("%v %s", emoji.Bookmark, s.Name)
+}
+
+func (s savedItem) Description() string {
+	var tags []string
+	for _, t := range s.Tags {
+		tags = append(tags, "#"+t)
+	}
+	meta := fmt.Sprintf("  %s%v  %s", s.Lang, emoji.Laptop, strings.Join(tags, " "))
+	note := s.Note
+	if note == "" {
+		note = s.Desc
+	}
+	return meta + "\n" + wrapText(Trim(note, getRepoListWidth()), uint(getRepoListWidth()))
+}
+
+func (s savedItem) FilterValue() string {
+	return s.Name + " " + strings.Join(s.Tags, " ") + " " + s.Note
+}
+
+// savedField is the bookmark field the input edits
+type savedField int
+
+const (
+	editNone savedField = iota
+	editTags
+	editNote
+)
+
+type savedModel struct {
+	savedList   list.Model
+	savedDetail viewport.Model
+	input       textinput.Model
+	editing     savedField
+}
+
+func (m savedModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *savedModel) updateSize() {
+	m.savedList.SetHeight(getRepoListHeight())
+	m.savedList.SetWidth(getRepoListWidth())
+	m.savedDetail.Width = getRepoDetailWidth()
+	m.savedDetail.Height = getRepoDetailHeight()
+}
+
+func (m savedModel) Update(tmsg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := tmsg.(type) {
+	case tea.WindowSizeMsg:
+		setTerminalSize(msg.Width, msg.Height)
+		m.updateSize()
+		return m, nil
+	case tea.KeyMsg:
+		if m.editing != editNone {
+			return m.updateEditing(msg)
+		}
+		selected, ok := m.savedList.SelectedItem().(savedItem)
+		switch msg.String() {
+		case "esc", "q":
+			return m, EventRestart()
+		case "d":
+			if !ok {
+				return m, nil
+			}
+			if err := storage.DeleteBookmark(selected.Url); err != nil {
+				return m, m.savedList.NewStatusMessage(failedStatusStyle.Render(err.Error()))
+			}
+			m.savedList.RemoveItem(m.savedList.Index())
+			m.showSelected()
+			return m, m.savedList.NewStatusMessage(statusStyle.Render(tr(msgDeleted, selected.Name)))
+		case "t", "e":
+			if !ok {
+				return m, nil
+			}
+			m.editing = editNote
+			m.input.Placeholder = tr(msgNotePlaceholder)
+			m.input.SetValue(selected.Note)
+			if msg.String() == "t" {
+				m.editing = editTags
+				m.input.Placeholder = tr(msgTagsPlaceholder)
+				m.input.SetValue(strings.Join(selected.Tags, ", "))
+			}
+			m.input.CursorEnd()
+			return m, m.input.Focus()
+		case "o":
+			if !ok {
+				return m, nil
+			}
+			if err := openBrowser(selected.Url); err != nil {
+				return m, m.savedList.NewStatusMessage(failedStatusStyle.Render(err.Error()))
+			}
+			return m, m.savedList.NewStatusMessage(statusStyle.Render(tr(msgOpened, selected.Url)))
+		}
+		var cmd tea.Cmd
+		m.savedList, cmd = m.savedList.Update(msg)
+		m.showSelected()
+		return m, cmd
+	}
+	var cmd tea.Cmd
+	m.savedList, cmd = m.savedList.Update(tmsg)
+	return m, cmd
+}
+
+func (m savedModel) updateEditing(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.editing = editNone
+		m.input.Blur()
+		return m, nil
+	case "enter":
+		selected, ok := m.savedList.SelectedItem().(savedItem)
+		if !ok {
+			return m, nil
+		}
+		if m.editing == editTags {
+			selected.Tags = parseTags(m.input.Value())
+		} else {
+			selected.Note = strings.TrimSpace(m.input.Value())
+		}
+		m.editing = editNone
+		m.input.Blur()
+		if err := storage.UpdateBookmark(selected.Url, selected.Tags, selected.Note); err != nil {
+			return m, m.savedList.NewStatusMessage(failedStatusStyle.Render(err.Error()))
+		}
+		cmd := m.savedList.SetItem(m.savedList.Index(), selected)
+		m.showSelected()
+		return m, cmd
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *savedModel) showSelected() {
+	selected, ok := m.savedList.SelectedItem().(savedItem)
+	if !ok {
+		m.savedDetail.SetContent(tr(msgNothingSaved, emoji.Bookmark))
+		return
+	}
+	m.savedDetail.SetContent(getSavedDetailContent(selected.Bookmark))
+}
+
+func (m savedModel) View() string {
+	listView := m.savedList.View()
+	if m.editing != editNone {
+		listView += "\n" + m.input.View()
+	}
+	return lipgloss.JoinHorizontal(
+		lipgloss.Top,
+		repoListStyle.Render(listView),
+		m.savedDetail.View(),
+	)
+}
+
+func newSavedModel() savedModel {
+	bookmarks, err := storage.Bookmarks()
+	items := make([]list.Item, len(bookmarks))
+	for i, b := range bookmarks {
+		items[i] = savedItem{b}
+	}
+	l := list.New(items, newAppItemDelegate(), getRepoListWidth(), getRepoListHeight())
+	l.Title = tr(msgSavedTitle, emoji.Bookmark, emoji.Bookmark)
+	l.StatusMessageLifetime = 3 * time.Second
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("t"), key.WithHelp("t", tr(msgKeyTags))),
+			key.NewBinding(key.WithKeys("e"), key.WithHelp("e", tr(msgKeyNote))),
+			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", tr(msgKeyDelete))),
+			key.NewBinding(key.WithKeys("o"), key.WithHelp("o", tr(msgKeyOpen))),
+		}
+	}
+	// q and esc go back to the chooser instead of quitting the program,
+	// and the letters are taken by the bookmark actions
+	l.KeyMap.Quit.SetHelp("q", tr(msgKeyBack))
+	l.SetFilteringEnabled(false)
+	input := textinput.New()
+	input.Prompt = "> "
+	m := savedModel{
+		savedList: l,
+		savedDetail: viewport.Model{
+			Width:  getRepoDetailWidth(),
+			Height: getRepoDetailHeight(),
+		},
+		input: input,
+	}
+	m.showSelected()
+	if err != nil {
+		m.savedDetail.SetContent(tr(msgLoadBookmarksFailed, emoji.TiredFace, err.Error()))
+	}
+	return m
+}
+
+func getSavedDetailContent(b storage.Bookmark) string {
+	name := fmt.Sprintf("%v %s ", emoji.TwoHearts, b.Name)
+	url := fmt.Sprintf("%v %s", emoji.Link, b.Url)
+	des := fmt.Sprintf("%v %s", emoji.OpenBook, b.Desc)
+	saved := tr(msgSavedAt, emoji.Calendar, b.CreatedAt.Format("2006-01-02 15:04"))
+	content := fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s", name, url, des, saved)
+	if len(b.Tags) > 0 {
+		content += fmt.Sprintf("\n\n%v %s", emoji.Label, strings.Join(b.Tags, ", "))
+	}
+	if b.Note != "" {
+		content += fmt.Sprintf("\n\n%v %s", emoji.Memo, wrapText(b.Note, uint(getRepoDetailWidth()-4)))
+	}
+	if b.Analysis != nil {
+		answer, _ := json.Marshal(b.Analysis)
+		content += "\n\n\n" + formatAI(string(answer))
+	}
+	return content
+}
+
+func parseTags(s string) []string {
+	var tags []string
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "#")
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
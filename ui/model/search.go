@@ -0,0 +1,203 @@
+package model
+
+import (
+	"encoding/json"
+	"gitoday/service"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// searchField is a part of a repo the search looks at, the weight ranks a match
+// in the name above one in the AI answer
+type searchField struct {
+	text   string
+	weight int
+}
+
+func searchFields(r repoItem) []searchField {
+	fields := []searchField{{r.Name, 4}, {r.Lang, 3}, {r.Desc, 2}}
+	var a service.ChatResponse
+	if r.AIAnswer != "" && json.Unmarshal([]byte(r.AIAnswer), &a) == nil {
+		answer := append([]string{a.What}, a.Why...)
+		fields = append(fields, searchField{strings.Join(append(answer, a.How...), "\n"), 1})
+	}
+	return fields
+}
+
+// searchScore ranks r against the terms of a query, every term has to match one
+// of the fields and 0 means r does not match
+func searchScore(r repoItem, terms []string) int {
+	fields := searchFields(r)
+	total := 0
+	for _, term := range terms {
+		best := 0
+		for _, f := range fields {
+			if score, _ := fuzzyMatch(f.text, term); score*f.weight > best {
+				best = score * f.weight
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
+
+// rankItems are the repos matching every term, best first, ties keep their order.
+// The terms are kept in the items so they highlight the matches.
+func rankItems(items []list.Item, terms []string) []list.Item {
+	type ranked struct {
+		item  repoItem
+		score int
+	}
+	var matches []ranked
+	for _, item := range items {
+		r := item.(repoItem)
+		r.search = terms
+		if len(terms) == 0 {
+			matches = append(matches, ranked{r, 0})
+		} else if score := searchScore(r, terms); score > 0 {
+			matches = append(matches, ranked{r, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	res := make([]list.Item, len(matches))
+	for i, m := range matches {
+		res[i] = m.item
+	}
+	return res
+}
+
+// fuzzyMatch finds term in s ignoring case, it returns a score and the rune positions
+// of the match. A substring scores highest and more at the start of a word, otherwise
+// the letters of term have to appear in order within a window of three times its length.
+func fuzzyMatch(s, term string) (int, []int) {
+	text, pattern := lowerRunes(s), lowerRunes(term)
+	if len(pattern) == 0 || len(pattern) > len(text) {
+		return 0, nil
+	}
+	best, bestPos := 0, []int(nil)
+	for i := indexRunes(text, pattern, 0); i >= 0; i = indexRunes(text, pattern, i+1) {
+		score := 100 + 10*len(pattern)
+		if wordStart(text, i) {
+			score += 50
+		}
+		if score > best {
+			best, bestPos = score, runeSpan(i, len(pattern))
+		}
+	}
+	if best > 0 {
+		return best, bestPos
+	}
+	window := 3 * len(pattern)
+	for start := range text {
+		if text[start] != pattern[0] {
+			continue
+		}
+		pos := []int{start}
+		for i := start + 1; i < len(text) && i-start < window && len(pos) < len(pattern); i++ {
+			if text[i] == pattern[len(pos)] {
+				pos = append(pos, i)
+			}
+		}
+		if len(pos) < len(pattern) {
+			continue
+		}
+		// the fewer letters between the matched ones the better
+		gaps := pos[len(pos)-1] - start + 1 - len(pattern)
+		score := 10*len(pattern) - gaps
+		if wordStart(text, start) {
+			score += 20
+		}
+		if score > best {
+			best, bestPos = score, pos
+		}
+	}
+	return best, bestPos
+}
+
+// highlight marks where the terms match s, every occurrence of a term found as
+// a substring and the best fuzzy match of the others
+func highlight(s string, terms []string) string {
+	if len(terms) == 0 || s == "" {
+		return s
+	}
+	runes, text := []rune(s), lowerRunes(s)
+	marked := make([]bool, len(runes))
+	for _, term := range terms {
+		pattern := lowerRunes(term)
+		if len(pattern) == 0 {
+			continue
+		}
+		found := false
+		for i := indexRunes(text, pattern, 0); i >= 0; i = indexRunes(text, pattern, i+len(pattern)) {
+			for _, p := range runeSpan(i, len(pattern)) {
+				marked[p] = true
+			}
+			found = true
+		}
+		if !found {
+			_, pos := fuzzyMatch(s, term)
+			for _, p := range pos {
+				marked[p] = true
+			}
+		}
+	}
+	var b strings.Builder
+	for i := 0; i < len(runes); {
+		j := i
+		for j < len(runes) && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			b.WriteString(matchStyle.Render(string(runes[i:j])))
+		} else {
+			b.WriteString(string(runes[i:j]))
+		}
+		i = j
+	}
+	return b.String()
+}
+
+// lowerRunes lowers every rune on its own so the positions stay those of s
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+func indexRunes(text, pattern []rune, from int) int {
+	for i := from; i+len(pattern) <= len(text); i++ {
+		match := true
+		for j, p := range pattern {
+			if text[i+j] != p {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+func wordStart(text []rune, i int) bool {
+	return i == 0 || !unicode.IsLetter(text[i-1]) && !unicode.IsDigit(text[i-1])
+}
+
+func runeSpan(start, n int) []int {
+	span := make([]int, n)
+	for i := range span {
+		span[i] = start + i
+	}
+	return span
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	cases := []struct {
+		s, term string
+		pos     []int
+	}{
+		{"neovim/neovim", "VIM", []int{3, 4, 5}},
+		// a word start is preferred over an earlier match inside a word
+		{"pgvector vec", "vec", []int{9, 10, 11}},
+		{"a fast tool", "fst", []int{2, 4, 5}},
+		{"fabrics", "fst", nil},
+		{"go", "golang", nil},
+	}
+	for _, c := range cases {
+		score, pos := fuzzyMatch(c.s, c.term)
+		if !reflect.DeepEqual(pos, c.pos) || (score > 0) != (c.pos != nil) {
+			t.Errorf("fuzzyMatch(%q, %q) = %d %v, want %v", c.s, c.term, score, pos, c.pos)
+		}
+	}
+	exact, _ := fuzzyMatch("a fast tool", "fast")
+	fuzzy, _ := fuzzyMatch("a fast tool", "fst")
+	if exact <= fuzzy {
+		t.Errorf("substring score %d is not above fuzzy score %d", exact, fuzzy)
+	}
+}
+
+func TestRankItems(t *testing.T) {
+	items := []list.Item{
+		repoItem{Name: "o/editor", Desc: "a rust tool", AIAnswer: `{"what":"an editor","why":["rust is fast"],"how":["tree-sitter"]}`, Url: "1"},
+		repoItem{Name: "o/rust", Desc: "the language", Url: "2"},
+		repoItem{Name: "o/web", Desc: "a browser", Lang: "Rust", Url: "3"},
+		repoItem{Name: "o/other", Desc: "nothing", Url: "4"},
+	}
+	var urls []string
+	for _, i := range rankItems(items, []string{"rust"}) {
+		urls = append(urls, i.(repoItem).Url)
+	}
+	// a name match beats the language, which beats the description
+	if !reflect.DeepEqual(urls, []string{"2", "3", "1"}) {
+		t.Errorf("ranked %v", urls)
+	}
+	ranked := rankItems(items, []string{"rust", "sitter"})
+	if len(ranked) != 1 || ranked[0].(repoItem).Url != "1" {
+		t.Errorf("every term has to match, got %v", ranked)
+	}
+	if got := ranked[0].(repoItem).search; !reflect.DeepEqual(got, []string{"rust", "sitter"}) {
+		t.Errorf("terms are not kept for highlighting: %v", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.ANSI)
+	defer lipgloss.SetColorProfile(termenv.Ascii)
+	got := highlight("Rust and rust\nfast", []string{"rust", "fst"})
+	if want := matchStyle.Render("Rust") + " and " + matchStyle.Render("rust") + "\n" + matchStyle.Render("f") + "a" + matchStyle.Render("st"); got != want {
+		t.Errorf("highlight = %q, want %q", got, want)
+	}
+	if got := highlight("nothing", []string{"rust"}); got != "nothing" {
+		t.Errorf("highlight changed %q", got)
+	}
+}
@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+type sortKey int
+
+const (
+	sortByRank sortKey = iota
+	sortByTodayStar
+	sortByStar
+	sortByFork
+	sortByName
+)
+
+// label is the key in the current locale
+func (k sortKey) label() string {
+	switch k {
+	case sortByTodayStar:
+		return tr(msgSortTodayStar)
+	case sortByStar:
+		return tr(msgSortStar)
+	case sortByFork:
+		return tr(msgSortFork)
+	case sortByName:
+		return tr(msgSortName)
+	default:
+		return tr(msgSortRank)
+	}
+}
+
+// next cycles through the keys in the order of the key binding help
+func (k sortKey) next() sortKey {
+	return (k + 1) % (sortByName + 1)
+}
+
+// ascending is the natural direction of the key: rank and name go up, the counts go
+// down so the biggest come first
+func (k sortKey) ascending() bool {
+	return k == sortByRank || k == sortByName
+}
+
+type repoSort struct {
+	key       sortKey
+	ascending bool
+}
+
+func (s repoSort) label() string {
+	if s.key == sortByRank && s.ascending {
+		return ""
+	}
+	arrow := "▼"
+	if s.ascending {
+		arrow = "▲"
+	}
+	return fmt.Sprintf(" · %s %s", s.key.label(), arrow)
+}
+
+func (s repoSort) less(a, b repoItem) bool {
+	if !s.ascending {
+		a, b = b, a
+	}
+	switch s.key {
+	case sortByTodayStar:
+		return a.TodayStarCount < b.TodayStarCount
+	case sortByStar:
+		return a.StarCount < b.StarCount
+	case sortByFork:
+		return a.ForkCount < b.ForkCount
+	case sortByName:
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	default:
+		return a.Index < b.Index
+	}
+}
+
+// sortItems returns the items in the sort order, ties keep their github rank
+func sortItems(items []list.Item, s repoSort) []list.Item {
+	sorted := make([]list.Item, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].(repoItem), sorted[j].(repoItem)
+		if s.less(a, b) {
+			return true
+		}
+		if s.less(b, a) {
+			return false
+		}
+		return a.Index < b.Index
+	})
+	return sorted
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+func TestSortItems(t *testing.T) {
+	items := []list.Item{
+		repoItem{Index: 0, Name: "beta", StarCount: 10, ForkCount: 3, TodayStarCount: 5},
+		repoItem{Index: 1, Name: "Alpha", StarCount: 30, ForkCount: 1, TodayStarCount: 5},
+		repoItem{Index: 2, Name: "gamma", StarCount: 20, ForkCount: 2, TodayStarCount: 9},
+	}
+	cases := []struct {
+		sort repoSort
+		want []string
+	}{
+		{repoSort{sortByRank, true}, []string{"beta", "Alpha", "gamma"}},
+		{repoSort{sortByRank, false}, []string{"gamma", "Alpha", "beta"}},
+		{repoSort{sortByStar, false}, []string{"Alpha", "gamma", "beta"}},
+		{repoSort{sortByFork, true}, []string{"Alpha", "gamma", "beta"}},
+		// ties keep the github rank
+		{repoSort{sortByTodayStar, false}, []string{"gamma", "beta", "Alpha"}},
+		{repoSort{sortByName, true}, []string{"Alpha", "beta", "gamma"}},
+	}
+	for _, c := range cases {
+		sorted := sortItems(items, c.sort)
+		for i, name := range c.want {
+			if got := sorted[i].(repoItem).Name; got != name {
+				t.Errorf("sort by %s ascending=%v: item %d = %s, want %s", c.sort.key.label(), c.sort.ascending, i, got, name)
+			}
+		}
+	}
+	if items[0].(repoItem).Name != "beta" {
+		t.Error("sortItems modified its input")
+	}
+}
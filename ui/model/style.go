@@ -15,6 +15,9 @@ const (
 	listColor           = "#fe8019"
 	listPaneBorderColor = "#3c3836"
 	inactivePaneColor   = "#928374"
+
+	defaultTerminalWidth  = 120
+	defaultTerminalHeight = 40
 )
 
 var (
@@ -28,6 +31,9 @@ func getRepoListWidth() int {
 func getRepoListHeight() int {
 	return int(terminalHeight.Load()) * 5 / 6
 }
+func getDeveloperListWidth() int {
+	return int(terminalWidth.Load()) * 2 / 3
+}
 func getRepoDetailWidth() int {
 	return int(terminalWidth.Load()) * 2 / 3
 }
@@ -41,36 +47,105 @@ func setTerminalSize(w, h int) {
 func init() {
 	s, err := tsize.GetSize()
 	if err != nil {
-		panic(err)
+		// not attached to a terminal, e.g. headless mode or tests,
+		// the first tea.WindowSizeMsg fixes the size once the TUI starts
+		setTerminalSize(defaultTerminalWidth, defaultTerminalHeight)
+		return
 	}
 	setTerminalSize(s.Width, s.Height)
 }
 
+// theme is the palette of the TUI, picked by name in the config file
+type theme struct {
+	accent   string
+	border   string
+	inactive string
+	text     string
+	up       string
+	down     string
+	checkbox string
+}
+
+var themes = map[string]theme{
+	"default": {
+		accent:   "#b8bb26",
+		border:   listPaneBorderColor,
+		inactive: inactivePaneColor,
+		text:     "#282828",
+		up:       "#b8bb26",
+		down:     "#fb4934",
+		checkbox: "212",
+	},
+	"light": {
+		accent:   "#79740e",
+		border:   "#d5c4a1",
+		inactive: "#7c6f64",
+		text:     "#fbf1c7",
+		up:       "#79740e",
+		down:     "#9d0006",
+		checkbox: "#8f3f71",
+	},
+}
+
 var (
-	checkboxStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
-	baseStyle     = lipgloss.NewStyle().
-			PaddingLeft(1).
-			PaddingRight(1).
-			Foreground(lipgloss.Color("#282828"))
+	checkboxStyle        lipgloss.Style
+	upStyle              lipgloss.Style
+	downStyle            lipgloss.Style
+	statusStyle          lipgloss.Style
+	failedStatusStyle    lipgloss.Style
+	matchStyle           lipgloss.Style
+	baseStyle            lipgloss.Style
+	baseListStyle        lipgloss.Style
+	repoListStyle        lipgloss.Style
+	msgValueVPStyle      lipgloss.Style
+	modeStyle            lipgloss.Style
+	repoDetailTitleStyle lipgloss.Style
+)
+
+func init() {
+	applyTheme(themes["default"])
+}
+
+// SetTheme switches the TUI palette, the themes are default and light.
+func SetTheme(name string) error {
+	t, ok := themes[name]
+	if !ok {
+		return fmt.Errorf("unknown theme %q", name)
+	}
+	applyTheme(t)
+	return nil
+}
+
+func applyTheme(t theme) {
+	checkboxStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.checkbox))
+	upStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.up))
+	downStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.down))
+	statusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.accent))
+	failedStatusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.down))
+	matchStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.accent)).Bold(true).Underline(true)
+	baseStyle = lipgloss.NewStyle().
+		PaddingLeft(1).
+		PaddingRight(1).
+		Foreground(lipgloss.Color(t.text))
 
 	baseListStyle = lipgloss.NewStyle().PaddingTop(1).PaddingRight(2).PaddingLeft(1).PaddingBottom(1)
 
 	repoListStyle = baseListStyle.
-			Border(lipgloss.NormalBorder(), false, true, false, false).
-			BorderForeground(lipgloss.Color(listPaneBorderColor))
+		Border(lipgloss.NormalBorder(), false, true, false, false).
+		BorderForeground(lipgloss.Color(t.border))
 
 	msgValueVPStyle = baseListStyle.Width(150).PaddingLeft(3)
 
 	modeStyle = baseStyle.
-			Align(lipgloss.Center).
-			Bold(true).
-			Background(lipgloss.Color("#b8bb26"))
+		Align(lipgloss.Center).
+		Bold(true).
+		Background(lipgloss.Color(t.accent))
 
 	repoDetailTitleStyle = baseStyle.
-				Bold(true).
-				Background(lipgloss.Color(inactivePaneColor)).
-				Align(lipgloss.Left)
-)
+		Bold(true).
+		Background(lipgloss.Color(t.inactive)).
+		Align(lipgloss.Left)
+}
 
 func checkbox(label string, checked bool) string {
 	if checked {